@@ -5,8 +5,11 @@ import (
     "log"
     "os"
     "os/signal"
+    "strings"
 
     "tunesday/internal/app"
+    "tunesday/internal/config"
+    "tunesday/internal/i18n"
     "tunesday/internal/playlist"
     "tunesday/internal/storage"
     "tunesday/internal/termui"
@@ -15,6 +18,41 @@ import (
 func main() {
     // quick flag parsing (minimal)
     args := os.Args[1:]
+    team, args := teamFlag(args)
+    if team == "" {
+        team = os.Getenv("TUNESDAY_TEAM")
+    }
+
+    cfg, err := config.Load(config.Path())
+    if err != nil {
+        log.Fatal(err)
+    }
+    base := cfg // without the settings of the team
+    if team == "" {
+        team = cfg.DefaultTeam
+    }
+    if names := cfg.TeamNames(); team == "" && len(names) == 1 {
+        team = names[0]
+    }
+    if team != "" {
+        if cfg, err = cfg.ForTeam(team); err != nil {
+            log.Fatal(err)
+        }
+    }
+    lang := cfg.Language
+    if lang == "" {
+        lang = i18n.Detect(os.Getenv)
+    }
+    if err := i18n.Use(lang); err != nil {
+        log.Fatal(err)
+    }
+    skin := cfg.Skin
+    if s := os.Getenv("TUNESDAY_SKIN"); s != "" {
+        skin = s
+    }
+    if err := termui.UseSkin(skin, cfg.Skins); err != nil {
+        log.Fatal(err)
+    }
     for _, a := range args {
         if a == "--radio" {
             termui.PrintTunesdayRadioHeader()
@@ -25,16 +63,103 @@ func main() {
     ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
     defer stop()
 
+    interactive := len(args) == 0 || strings.HasPrefix(args[0], "-")
+    if team == "" && len(cfg.Teams) > 0 {
+        switch {
+        case interactive:
+            if team = termui.PickTeam(ctx, cfg.TeamNames()); team == "" {
+                return
+            }
+            cfg, _ = cfg.ForTeam(team)
+            if cfg.Language != lang && cfg.Language != "" {
+                if err := i18n.Use(cfg.Language); err != nil {
+                    log.Fatal(err)
+                }
+            }
+            if cfg.Skin != skin && os.Getenv("TUNESDAY_SKIN") == "" {
+                if err := termui.UseSkin(cfg.Skin, cfg.Skins); err != nil {
+                    log.Fatal(err)
+                }
+            }
+        case args[0] != "teams":
+            log.Fatalf("there are several teams (%s), pick one with --team", strings.Join(cfg.TeamNames(), ", "))
+        }
+    }
+
     dataFile := os.Getenv("TUNESDAY_DATA_FILE")
+    if team != "" {
+        dataFile = cfg.DataFile(team)
+    }
     if dataFile == "" {
         dataFile = "tunesday.json"
     }
 
-    store := storage.NewFileStore(dataFile)
+    store, err := openStore(dataFile, cfg)
+    if err != nil {
+        log.Fatal(err)
+    }
     yt := playlist.NewYouTube()
     application := app.New(store, yt)
+    if len(cfg.Teams) > 0 {
+        var teams []app.Team
+        for _, name := range cfg.TeamNames() {
+            if name == team {
+                teams = append(teams, app.Team{Name: name, Store: store})
+                continue
+            }
+            // opened only for the views across teams, where a team that
+            // can't be opened is reported rather than stopping the rest
+            path := cfg.DataFile(name)
+            teams = append(teams, app.Team{Name: name, Open: func() (storage.Store, error) {
+                tc, err := base.ForTeam(name)
+                if err != nil {
+                    return nil, err
+                }
+                return openStore(path, tc)
+            }})
+        }
+        application.SetTeams(team, teams)
+    }
 
     if err := application.Run(ctx, args); err != nil {
         log.Fatal(err)
     }
 }
+
+// teamFlag takes --team name or --team=name out of args, wherever it is.
+func teamFlag(args []string) (string, []string) {
+    var team string
+    rest := make([]string, 0, len(args))
+    for i := 0; i < len(args); i++ {
+        switch a := args[i]; {
+        case a == "--team" && i+1 < len(args):
+            team = args[i+1]
+            i++
+        case strings.HasPrefix(a, "--team="):
+            team = strings.TrimPrefix(a, "--team=")
+        default:
+            rest = append(rest, a)
+        }
+    }
+    return team, rest
+}
+
+// openStore opens a data file with the backups and encryption of cfg.
+func openStore(path string, cfg config.Config) (storage.Store, error) {
+    files := storage.NewFileStore(path)
+    files.SetRetention(storage.Retention{Keep: cfg.Backups.Keep, Daily: cfg.Backups.Daily, Weekly: cfg.Backups.Weekly})
+    store := storage.Encrypted(files)
+    passphrase, err := cfg.Encryption.Passphrase()
+    if err != nil {
+        return nil, err
+    }
+    if passphrase == "" {
+        return store, nil
+    }
+    key, err := storage.NewKey(passphrase)
+    if err != nil {
+        return nil, err
+    }
+    store.SetKey(key)
+    return store, nil
+}
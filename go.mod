@@ -4,16 +4,17 @@ go 1.23.0
 
 require (
 	atomicgo.dev/keyboard v0.2.9
+	github.com/containerd/console v1.0.3
 	github.com/kkdai/youtube/v2 v2.10.4
+	golang.org/x/crypto v0.33.0
+	golang.org/x/sys v0.30.0
 )
 
 require (
 	github.com/bitly/go-simplejson v0.5.1 // indirect
-	github.com/containerd/console v1.0.3 // indirect
 	github.com/dlclark/regexp2 v1.11.5 // indirect
 	github.com/dop251/goja v0.0.0-20250125213203-5ef83b82af17 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.4+incompatible // indirect
 	github.com/google/pprof v0.0.0-20250208200701-d0013a598941 // indirect
-	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
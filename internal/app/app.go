@@ -1,14 +1,16 @@
 package app
 
 import (
-    "bufio"
     "context"
+    "errors"
     "fmt"
-    "math/rand"
     "os"
     "os/signal"
+    "strings"
     "time"
 
+    "tunesday/internal/core"
+    "tunesday/internal/i18n"
     "tunesday/internal/playlist"
     "tunesday/internal/storage"
     "tunesday/internal/termui"
@@ -17,13 +19,41 @@ import (
 type App struct {
     store storage.Store
     yt    playlist.TitleProvider
+    team  string // the team store belongs to, empty without teams
+    teams []Team
+}
+
+// Team is a workspace with a store of its own.
+type Team struct {
+    Name  string
+    Store storage.Store
+    // Open opens the store when Store is nil, so that the stores of the
+    // other teams are only opened for the views across teams.
+    Open func() (storage.Store, error)
+}
+
+func (t Team) open() (storage.Store, error) {
+    if t.Store != nil || t.Open == nil {
+        return t.Store, nil
+    }
+    return t.Open()
 }
 
 func New(store storage.Store, yt playlist.TitleProvider) *App {
     return &App{store: store, yt: yt}
 }
 
+// SetTeams says which team the app's store belongs to and which teams there
+// are, for the views across teams.
+func (a *App) SetTeams(current string, teams []Team) {
+    a.team, a.teams = current, teams
+}
+
 func (a *App) Run(ctx context.Context, args []string) error {
+    if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+        return a.runCommand(ctx, args[0], args[1:])
+    }
+
     skipTuesdayCheck := false
     for _, arg := range args {
         if arg == "--force-tunesday" {
@@ -36,14 +66,25 @@ func (a *App) Run(ctx context.Context, args []string) error {
         return nil
     }
 
-    rand.Seed(time.Now().UnixNano())
-
-    data, err := a.store.Load(ctx)
+    data, err := a.load(ctx)
     if err != nil {
         return err
     }
+    if problems := data.Check(a.yt.NormalizeYouTubeID); len(problems) > 0 {
+        termui.WarnProblems(len(problems))
+    }
+    history, err := a.loadHistory(ctx)
+    if err != nil {
+        return err
+    }
+    termui.SetHistory(history)
+    defer termui.SetHistory(nil)
+    save := func(ctx context.Context) {
+        _ = a.store.Save(ctx, data)
+        _ = a.saveHistory(ctx, history)
+    }
 
-    scanner := bufio.NewScanner(os.Stdin)
+    scanner := termui.Input()
 
     termui.HideCursor()
     defer termui.ShowCursor()
@@ -53,43 +94,131 @@ func (a *App) Run(ctx context.Context, args []string) error {
     signal.Notify(sigC, os.Interrupt)
     go func() {
         <-sigC
-        _ = a.store.Save(context.Background(), data)
+        save(context.Background())
         os.Exit(0)
     }()
 
+    title := i18n.T("Tunesday Menu")
+    if a.team != "" {
+        title = i18n.T("Tunesday Menu · %s", a.team)
+    }
     for {
-        idx := termui.ShowMenu(ctx, "Tunesday Menu", []string{
-            "Select todays tune provider",
-            "Manually add a tune to list",
-            "Get complete list of tunes",
-            "Manage Tunesday participants",
-            "Get youtube playlist link",
-            "Exit",
+        if s := data.LookupSession(time.Now()); s != nil {
+            termui.SetSessionTheme(s.Theme)
+        }
+        idx := termui.ShowMenu(ctx, title, []string{
+            i18n.T("Select todays tune provider"),
+            i18n.T("Plan todays round (theme, providers)"),
+            i18n.T("Manually add a tune to list"),
+            i18n.T("Get complete list of tunes"),
+            i18n.T("Rate tunes"),
+            i18n.T("Statistics"),
+            i18n.T("Manage Tunesday participants"),
+            i18n.T("Get youtube playlist link"),
+            undoLabel(history),
+            redoLabel(history),
+            i18n.T("Exit"),
         })
 
         switch idx {
-        case -1, -2, 5: // Exit
-            _ = a.store.Save(ctx, data)
-            fmt.Println("Goodbye!")
+        case -1, -2, 10: // Exit
+            save(ctx)
+            termui.Goodbye()
             return nil
         case 0: // Select provider
-            selected := termui.SelectProvider(ctx, data)
-            if selected != "" {
-                termui.AddTuneWithProvider(ctx, data, scanner, selected, a.yt)
+            for _, draw := range termui.SelectProvider(ctx, data) {
+                termui.AddTuneWithProvider(ctx, data, scanner, draw, a.yt)
                 termui.PressEnterToContinue()
             }
-        case 1: // Add tune
+        case 1: // Plan round
+            termui.PlanRound(ctx, data, scanner)
+        case 2: // Add tune
             termui.AddTune(data, scanner)
-        case 2: // List tunes
-            termui.ListTunes(data, scanner)
-            termui.PressEnterToContinue()
-        case 3: // Manage participants
+        case 3: // List tunes
+            termui.ListTunes(ctx, data, scanner)
+        case 4: // Rate tunes
+            termui.RateTunes(ctx, data)
+        case 5: // Statistics
+            termui.ShowStatistics(ctx, data)
+        case 6: // Manage participants
             termui.ManageParticipants(ctx, data, scanner)
-        case 4: // Playlist link
-            termui.PrintYouTubePlaylistLink(data)
+        case 7: // Playlist link
+            termui.PrintYouTubePlaylistLink(ctx, data)
+            termui.PressEnterToContinue()
+        case 8: // Undo
+            termui.Undo(data)
+            termui.PressEnterToContinue()
+        case 9: // Redo
+            termui.Redo(data)
             termui.PressEnterToContinue()
         }
         // Persist after each loop iteration
-        _ = a.store.Save(ctx, data)
+        save(ctx)
+    }
+}
+
+// undoLabel names the action Undo would undo in the main menu.
+func undoLabel(h *core.History) string {
+    if c, ok := h.LastUndo(); ok {
+        return i18n.T("Undo last action (%s)", c.Action)
+    }
+    return i18n.T("Undo last action")
+}
+
+func redoLabel(h *core.History) string {
+    if c, ok := h.LastRedo(); ok {
+        return i18n.T("Redo (%s)", c.Action)
+    }
+    return i18n.T("Redo")
+}
+
+// load loads the data. When the file can't be read, the error shows where
+// the mistake is and points to the doctor command; when it is encrypted, it
+// says how to give the passphrase.
+func (a *App) load(ctx context.Context) (*core.Data, error) {
+    data, err := a.store.Load(ctx)
+    var de *storage.DecodeError
+    switch {
+    case errors.As(err, &de):
+        return nil, fmt.Errorf("%w\n%s\nrun \"tunesday doctor\" for help with it", err, pointAt(de))
+    case errors.Is(err, storage.ErrEncrypted):
+        return nil, fmt.Errorf("%w\nset TUNESDAY_PASSPHRASE, or \"encryption\": {\"key_file\": ...} in the config file", err)
+    }
+    return data, err
+}
+
+// pointAt shows the line of a decode error with a caret under the mistake.
+func pointAt(de *storage.DecodeError) string {
+    prefix := fmt.Sprintf("%5d | ", de.Line)
+    pad := []rune(de.Text)
+    if len(pad) > de.Column-1 {
+        pad = pad[:de.Column-1]
+    }
+    for i, r := range pad {
+        if r != '\t' {
+            pad[i] = ' '
+        }
+    }
+    return prefix + de.Text + "\n" + strings.Repeat(" ", len(prefix)-2) + "| " + string(pad) + "^"
+}
+
+// loadHistory returns today's undo history, or an empty one kept in memory
+// when the store doesn't keep history.
+func (a *App) loadHistory(ctx context.Context) (*core.History, error) {
+    h := &core.History{}
+    if hs, ok := a.store.(storage.HistoryStore); ok {
+        var err error
+        if h, err = hs.LoadHistory(ctx); err != nil {
+            return nil, err
+        }
+    }
+    h.Expire(time.Now())
+    return h, nil
+}
+
+func (a *App) saveHistory(ctx context.Context, h *core.History) error {
+    if hs, ok := a.store.(storage.HistoryStore); ok {
+        return hs.SaveHistory(ctx, h)
     }
+    return nil
 }
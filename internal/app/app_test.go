@@ -0,0 +1,490 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tunesday/internal/core"
+	"tunesday/internal/golden"
+	"tunesday/internal/playlist"
+	"tunesday/internal/storage"
+	"tunesday/internal/termui"
+)
+
+type memStore struct {
+	data  *core.Data
+	saves int
+}
+
+func (m *memStore) Load(ctx context.Context) (*core.Data, error) { return m.data, nil }
+
+func (m *memStore) Save(ctx context.Context, d *core.Data) error {
+	m.saves++
+	return nil
+}
+
+type fakeYouTube struct{}
+
+func (fakeYouTube) NormalizeYouTubeID(raw string) (string, bool) {
+	if i := strings.Index(raw, "youtu.be/"); i >= 0 {
+		return raw[i+len("youtu.be/"):], true
+	}
+	return "", false
+}
+
+func (fakeYouTube) FetchTitle(ctx context.Context, id string) (string, error) {
+	return "Title of " + id, nil
+}
+
+// playlistYouTube lists a playlist of its own.
+type playlistYouTube struct {
+	fakeYouTube
+	entries []playlist.PlaylistEntry
+}
+
+func (y *playlistYouTube) NormalizeYouTubeID(raw string) (string, bool) {
+	if _, id, ok := strings.Cut(raw, "watch?v="); ok {
+		return id, true
+	}
+	return y.fakeYouTube.NormalizeYouTubeID(raw)
+}
+
+func (y *playlistYouTube) Playlist(ctx context.Context, url string) ([]playlist.PlaylistEntry, error) {
+	return y.entries, nil
+}
+
+func TestRunDrawAddExit(t *testing.T) {
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	store := &memStore{data: d}
+
+	var out bytes.Buffer
+	termui.Use(termui.Script{
+		// Select provider, add the tune, then Exit from the main menu.
+		Keys:   termui.Press("enter", "enter", "end", "enter"),
+		Lines:  []string{"https://youtu.be/abc123", ""},
+		Height: 40,
+		Start:  time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC),
+	}.Console(&out))
+	t.Cleanup(func() { termui.Use(termui.Terminal()) })
+
+	if err := New(store, fakeYouTube{}).Run(context.Background(), []string{"--force-tunesday"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tunes) != 1 || d.Tunes[0].Name != "Title of abc123" || d.Tunes[0].ParticipantID != ann.ID {
+		t.Fatalf("tunes = %+v", d.Tunes)
+	}
+	if store.saves == 0 {
+		t.Fatal("data was never saved")
+	}
+
+	// drop the repeated still frames of the draw animation
+	var frames []string
+	for _, f := range strings.Split(out.String(), "\x1b[H\x1b[2J") {
+		if len(frames) == 0 || frames[len(frames)-1] != f {
+			frames = append(frames, f)
+		}
+	}
+	got := strings.Join(frames, "\n──── clear screen ────\n")
+
+	golden.Check(t, "draw_add_exit", got)
+}
+
+func TestUndoCommand(t *testing.T) {
+	store := storage.NewFileStore(filepath.Join(t.TempDir(), "tunesday.json"))
+	ctx := context.Background()
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	d.Tunes = []core.Tune{{ID: "abc", Name: "A tune", ParticipantID: ann.ID}}
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	a := New(store, fakeYouTube{})
+	if err := a.Run(ctx, []string{"vote", "Ann", "abc", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Run(ctx, []string{"undo"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := store.Load(ctx)
+	if len(got.Tunes[0].Votes) != 0 {
+		t.Fatalf("vote survived undo: %v", got.Tunes[0].Votes)
+	}
+	if err := a.Run(ctx, []string{"redo"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = store.Load(ctx)
+	if got.Tunes[0].Votes[ann.ID] != 5 {
+		t.Fatalf("redo did not bring the vote back: %v", got.Tunes[0].Votes)
+	}
+	if err := a.Run(ctx, []string{"redo"}); !errors.Is(err, core.ErrNothingToRedo) {
+		t.Fatalf("redo with nothing to redo: %v", err)
+	}
+}
+
+func TestBackupRestoreCommand(t *testing.T) {
+	store := storage.NewFileStore(filepath.Join(t.TempDir(), "tunesday.json"))
+	ctx := context.Background()
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	_ = d.RemoveParticipant(ann.ID)
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	a := New(store, fakeYouTube{})
+	if err := a.Run(ctx, []string{"backup", "list"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Run(ctx, []string{"backup", "restore", "--yes", "2"}); err == nil {
+		t.Fatal("restored a backup that doesn't exist")
+	}
+	if err := a.Run(ctx, []string{"backup", "restore", "--yes", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := store.Load(ctx)
+	if got.ParticipantByName("Ann") == nil {
+		t.Fatal("Ann is not back after the restore")
+	}
+}
+
+func TestDoctorCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tunesday.json")
+	file := `{
+  "version": 2,
+  "participants": {
+    "a": {"id": "a", "name": "Ann"},
+    "b": {"id": "b", "name": "ANN"},
+  },
+  "tunes": [{"name": "A tune", "link": "https://youtu.be/abc", "provider": "youtube"}]
+}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := storage.NewFileStore(path)
+	ctx := context.Background()
+	a := New(store, fakeYouTube{})
+
+	err := a.Run(ctx, []string{"vote", "Ann", "1", "5"})
+	if err == nil || !strings.Contains(err.Error(), "tunesday.json:6:3: invalid character '}'") ||
+		!strings.Contains(err.Error(), "    6 |   },\n      |   ^") || !strings.Contains(err.Error(), "tunesday doctor") {
+		t.Fatalf("loading the broken file: %v", err)
+	}
+
+	if err := a.Run(ctx, []string{"doctor", "--yes"}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Participants["b"].Name != "ANN (2)" || d.Tunes[0].ID != "abc" {
+		t.Fatalf("not repaired: %+v %+v", d.Participants["b"], d.Tunes[0])
+	}
+	if bs, _ := store.Backups(); len(bs) != 1 {
+		t.Fatalf("backups = %v, want the broken file", bs)
+	}
+	if err := a.Run(ctx, []string{"doctor"}); err != nil {
+		t.Fatalf("doctor after repair: %v", err)
+	}
+}
+
+func TestWrappedCommand(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	a := New(storage.NewFileStore(filepath.Join(dir, "tunesday.json")), fakeYouTube{})
+
+	bad := filepath.Join(dir, "wrapped.pdf")
+	if err := a.Run(ctx, []string{"wrapped", "--format", "pdf", "--out", bad}); err == nil {
+		t.Fatal("wrapped --format pdf succeeded")
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Errorf("an unknown format left %s behind: %v", bad, err)
+	}
+	md := filepath.Join(dir, "wrapped.md")
+	if err := a.Run(ctx, []string{"wrapped", "--out", md}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(md); err != nil || !strings.HasPrefix(string(b), "#") {
+		t.Errorf("wrapped.md = %q, %v", b, err)
+	}
+}
+
+func TestExportImportCommands(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	store := storage.NewFileStore(filepath.Join(dir, "tunesday.json"))
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	d.Tunes = []core.Tune{{ID: "abc", Link: "https://youtu.be/abc", Name: "A tune", ParticipantID: ann.ID}}
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	a := New(store, fakeYouTube{})
+	bad := filepath.Join(dir, "bad.csv")
+	if err := a.Run(ctx, []string{"export", "--table", "votes", "--out", bad}); err == nil {
+		t.Fatal("export --table votes succeeded")
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Errorf("an unknown table left %s behind: %v", bad, err)
+	}
+	archive := filepath.Join(dir, "all.tunesday")
+	if err := a.Run(ctx, []string{"export", "--out", archive}); err != nil {
+		t.Fatal(err)
+	}
+
+	other := storage.NewFileStore(filepath.Join(dir, "other.json"))
+	b := New(other, fakeYouTube{})
+	if err := b.Run(ctx, []string{"import", "--replace", "--yes", archive}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := other.Load(ctx)
+	if diff := core.Diff(d, got); len(diff) > 0 {
+		t.Fatalf("archive import differs: %q", diff)
+	}
+
+	sheet := filepath.Join(dir, "sheet.csv")
+	csv := "Title,URL,Provider\nA tune again,https://youtu.be/abc,Ann\nNew tune,https://youtu.be/new,Bob\n"
+	if err := os.WriteFile(sheet, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Run(ctx, []string{"import", "--yes", sheet}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = other.Load(ctx)
+	if len(got.Tunes) != 2 || got.Tunes[1].ID != "new" || got.ParticipantName(got.Tunes[1].ParticipantID) != "Bob" {
+		t.Fatalf("merged tunes = %+v", got.Tunes)
+	}
+	if err := b.Run(ctx, []string{"undo"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = other.Load(ctx); len(got.Tunes) != 1 {
+		t.Fatalf("undo left %d tunes", len(got.Tunes))
+	}
+}
+
+// parallelYouTube fails some fetches and remembers how many ran at once.
+type parallelYouTube struct {
+	fakeYouTube
+	mu            sync.Mutex
+	running, most int
+}
+
+func (y *parallelYouTube) FetchTitle(ctx context.Context, id string) (string, error) {
+	y.mu.Lock()
+	y.running++
+	y.most = max(y.most, y.running)
+	y.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	y.mu.Lock()
+	y.running--
+	y.mu.Unlock()
+	if id == "gone" {
+		return "", errors.New("video unavailable")
+	}
+	return "Title of " + id, nil
+}
+
+func TestImportLinksCommand(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	store := storage.NewFileStore(filepath.Join(dir, "tunesday.json"))
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	d.Tunes = []core.Tune{{ID: "old", Link: "https://youtu.be/old", Name: "Old", ParticipantID: ann.ID}}
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	chat := filepath.Join(dir, "chat.json")
+	export := `[
+		{"user": "U1", "ts": "1683043200", "text": "<https://youtu.be/one>"},
+		{"user": "U2", "user_profile": {"real_name": "Bobby"}, "ts": "1683648000", "text": "https://youtu.be/two and https://youtu.be/gone"},
+		{"user": "U3", "user_profile": {"real_name": "Guest"}, "ts": "1683648001", "text": "https://youtu.be/three https://youtu.be/old"},
+		{"user": "U2", "ts": "1684252800", "text": "again: https://youtu.be/one, see https://example.com"}
+	]`
+	mapping := filepath.Join(dir, "people.txt")
+	if err := os.WriteFile(chat, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mapping, []byte("# chat -> team\nU1 = Ann\nbobby = Bob\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	yt := &parallelYouTube{}
+	a := New(store, yt)
+	if err := a.Run(ctx, []string{"import-links", "--map", mapping, "--jobs", "3", "--yes", chat}); err != nil {
+		t.Fatal(err)
+	}
+	if yt.most < 2 || yt.most > 3 {
+		t.Errorf("%d titles fetched at once, want 2 or 3", yt.most)
+	}
+	got, _ := store.Load(ctx)
+	byID := make(map[string]core.Tune)
+	for _, tune := range got.Tunes {
+		byID[tune.ID] = tune
+	}
+	if len(got.Tunes) != 5 {
+		t.Fatalf("tunes = %+v", got.Tunes)
+	}
+	one := byID["one"]
+	if one.Name != "Title of one" || one.ParticipantID != ann.ID || !one.AddedAt.Equal(time.Unix(1683043200, 0)) {
+		t.Errorf("one = %+v", one)
+	}
+	if who := got.ParticipantName(byID["two"].ParticipantID); who != "Bob" {
+		t.Errorf("two brought by %q, want Bob", who)
+	}
+	if byID["three"].ParticipantID != "" || byID["gone"].Name != "https://youtu.be/gone" {
+		t.Errorf("three = %+v, gone = %+v", byID["three"], byID["gone"])
+	}
+
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Four</title>`+
+			`<link href="https://youtu.be/four"/><published>2023-06-06T19:00:00Z</published></entry></feed>`)
+	}))
+	defer feed.Close()
+	if err := a.Run(ctx, []string{"import-links", "--as", "Ann", "--yes", feed.URL}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = store.Load(ctx)
+	four := got.Tunes[len(got.Tunes)-1]
+	// the feed only knows when the video was uploaded, not when it was picked
+	if four.ID != "four" || four.Name != "Four" || four.ParticipantID != ann.ID || !four.AddedAt.IsZero() {
+		t.Errorf("four = %+v", four)
+	}
+
+	pl := &playlistYouTube{entries: []playlist.PlaylistEntry{
+		{ID: "five", VideoInfo: playlist.VideoInfo{Title: "Five", Channel: "Band", Duration: 3*time.Minute + 20*time.Second}},
+	}}
+	if err := New(store, pl).Run(ctx, []string{"import-links", "--as", "Ann", "--date", "2023-06-13", "--yes",
+		"https://www.youtube.com/playlist?list=PL1"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = store.Load(ctx)
+	five := got.Tunes[len(got.Tunes)-1]
+	if five.ID != "five" || five.Seconds != 200 || !five.AddedAt.Equal(time.Date(2023, 6, 13, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("five = %+v", five)
+	}
+	if err := a.Run(ctx, []string{"import-links", "--date", "13.06.2023", feed.URL}); err == nil {
+		t.Error("import-links took a date it can't read")
+	}
+}
+
+func TestTeamsCommand(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	web, ops := core.NewData(), core.NewData()
+	ann, _ := web.AddParticipant("Ann")
+	bob, _ := ops.AddParticipant("Bob")
+	web.Tunes = []core.Tune{
+		{Name: "Old", Link: "https://youtu.be/old", ParticipantID: ann.ID, AddedAt: now.AddDate(0, 0, -30)},
+		{Name: "Web pick", Link: "https://youtu.be/web", ParticipantID: ann.ID, AddedAt: now},
+	}
+	ops.Tunes = []core.Tune{{Name: "Ops pick", Link: "https://youtu.be/ops", ParticipantID: bob.ID, AddedAt: now}}
+
+	a := New(&memStore{data: web}, fakeYouTube{})
+	if err := a.Run(ctx, []string{"teams"}); err == nil {
+		t.Fatal("teams without teams: no error")
+	}
+	a.SetTeams("web", []Team{
+		{Name: "ops", Open: func() (storage.Store, error) { return &memStore{data: ops}, nil }},
+		{Name: "qa", Open: func() (storage.Store, error) { return nil, errors.New("no key file") }},
+		{Name: "web", Store: &memStore{data: web}},
+	})
+
+	out := stdout(t, func() error { return a.Run(ctx, []string{"teams"}) })
+	if !regexp.MustCompile(`\*\s+web\s+1\s+2\s+1\s+never`).MatchString(out) || !regexp.MustCompile(`\n\s+ops\s+1\s+1\s+1`).MatchString(out) ||
+		!regexp.MustCompile(`\n\s+qa\s+can't be read: no key file`).MatchString(out) {
+		t.Errorf("overview:\n%s", out)
+	}
+	out = stdout(t, func() error { return a.Run(ctx, []string{"teams", "tunes"}) })
+	if strings.Contains(out, "Old") || strings.Index(out, "Ops pick") > strings.Index(out, "Web pick") || !strings.Contains(out, "Bob") ||
+		!strings.Contains(out, "qa can't be read: no key file") {
+		t.Errorf("tunes this week:\n%s", out)
+	}
+	since := now.AddDate(0, 0, -40).Format("2006-01-02")
+	if out = stdout(t, func() error { return a.Run(ctx, []string{"teams", "tunes", "--since", since}) }); !strings.Contains(out, "Old") {
+		t.Errorf("tunes since %s:\n%s", since, out)
+	}
+}
+
+// stdout returns what f prints.
+func stdout(t *testing.T, f func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		var b bytes.Buffer
+		b.ReadFrom(r)
+		done <- b.String()
+	}()
+	err = f()
+	os.Stdout = orig
+	w.Close()
+	out := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestKeyCommand(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tunesday.json")
+	store := storage.NewFileStore(path)
+	d := core.NewData()
+	d.AddParticipant("Ann")
+	if err := store.Save(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New(store, fakeYouTube{}).Run(ctx, []string{"key", "rotate", "--new-key-file", keyFile, "--yes"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); bytes.Contains(b, []byte("Ann")) {
+		t.Fatalf("not encrypted:\n%s", b)
+	}
+	_, err := New(storage.NewFileStore(path), fakeYouTube{}).load(ctx)
+	if !errors.Is(err, storage.ErrEncrypted) || !strings.Contains(err.Error(), "TUNESDAY_PASSPHRASE") {
+		t.Errorf("load without the passphrase = %v", err)
+	}
+
+	keyed := storage.Encrypted(storage.NewFileStore(path))
+	k, _ := storage.NewKey("s3cret")
+	keyed.SetKey(k)
+	a := New(keyed, fakeYouTube{})
+	if got, err := a.load(ctx); err != nil || got.ParticipantByName("Ann") == nil {
+		t.Fatalf("load with the passphrase = %v, %v", got, err)
+	}
+	if err := a.Run(ctx, []string{"key", "remove", "--yes"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := storage.NewFileStore(path).Load(ctx); err != nil || got.ParticipantByName("Ann") == nil {
+		t.Errorf("after removing the key: %v, %v", got, err)
+	}
+}
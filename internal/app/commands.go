@@ -0,0 +1,621 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"tunesday/internal/config"
+	"tunesday/internal/core"
+	"tunesday/internal/stats"
+	"tunesday/internal/storage"
+	"tunesday/internal/termui"
+	"tunesday/internal/transfer"
+)
+
+// runCommand dispatches non-interactive subcommands such as "tunesday recount".
+func (a *App) runCommand(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "recount":
+		return a.recount(ctx, args)
+	case "vote":
+		return a.vote(ctx, args)
+	case "wrapped":
+		return a.wrapped(ctx, args)
+	case "skins":
+		return skins()
+	case "undo":
+		return a.undo(ctx, args, false)
+	case "redo":
+		return a.undo(ctx, args, true)
+	case "backup":
+		return a.backup(ctx, args)
+	case "doctor":
+		return a.doctor(ctx, args)
+	case "export":
+		return a.export(ctx, args)
+	case "import":
+		return a.importData(ctx, args)
+	case "import-links":
+		return a.importLinks(ctx, args)
+	case "key":
+		return a.key(ctx, args)
+	case "serve":
+		return a.serve(ctx, args)
+	case "teams":
+		return a.teamViews(ctx, args)
+	}
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// recount repairs the draw history and prints the counts before and after.
+func (a *App) recount(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("recount", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "show the result without saving")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	history, err := a.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	_ = history.Record("recount", data, time.Now())
+	before := data.Counts()
+	report := data.Recount(time.Now())
+	after := data.Counts()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Participant\tDrawn\tDelivered\tSkipped")
+	for _, p := range data.SortedParticipants() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Name,
+			change(before[p.ID].Drawn, after[p.ID].Drawn),
+			change(before[p.ID].Delivered, after[p.ID].Delivered),
+			change(before[p.ID].Skipped, after[p.ID].Skipped))
+	}
+	tw.Flush()
+	fmt.Printf("\nclosed %d stale draws, attributed %d tunes, added %d draws for past tunes\n",
+		report.ClosedDraws, report.AttributedTunes, report.SynthesizedDraws)
+
+	if *dryRun {
+		fmt.Println("dry run, nothing saved")
+		return nil
+	}
+	if err := a.store.Save(ctx, data); err != nil {
+		return err
+	}
+	return a.saveHistory(ctx, history)
+}
+
+func change(before, after int) string {
+	if before == after {
+		return fmt.Sprint(after)
+	}
+	return fmt.Sprintf("%d -> %d", before, after)
+}
+
+// vote records a rating: tunesday vote <participant> <tune> <1-5|up|down|clear>.
+// The tune is a YouTube video ID or its position in the tune list.
+func (a *App) vote(ctx context.Context, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("usage: tunesday vote <participant> <tune id or number> <1-5|up|down|clear>")
+	}
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	p := data.ParticipantByName(args[0])
+	if p == nil {
+		return fmt.Errorf("%w: %s", core.ErrParticipantNotFound, args[0])
+	}
+	i, err := data.FindTune(args[1])
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[1])
+	}
+	stars := 0
+	if args[2] != "clear" {
+		if stars, err = core.ParseRating(args[2]); err != nil {
+			return err
+		}
+	}
+	history, err := a.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	_ = history.Record(fmt.Sprintf("vote %s %s %s", p.Name, args[1], args[2]), data, time.Now())
+	if err := data.Vote(i, p.ID, stars); err != nil {
+		return err
+	}
+	t := data.Tunes[i]
+	fmt.Printf("%s: score %s\n", t.Name, t.ScoreLabel())
+	if err := a.store.Save(ctx, data); err != nil {
+		return err
+	}
+	return a.saveHistory(ctx, history)
+}
+
+// undo undoes, or with redo set redoes, the last change of today's session,
+// whether it was made in the app or by a command. With --list it shows what
+// can be undone and redone instead.
+func (a *App) undo(ctx context.Context, args []string, redo bool) error {
+	name := "undo"
+	if redo {
+		name = "redo"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	list := fs.Bool("list", false, "list the changes that can be undone and redone")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := a.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	if *list {
+		if len(history.Done) == 0 && len(history.Undone) == 0 {
+			fmt.Println("nothing to undo today")
+		}
+		for i := len(history.Done) - 1; i >= 0; i-- {
+			c := history.Done[i]
+			fmt.Printf("undo  %s  %s\n", c.At.Format("15:04"), c.Action)
+		}
+		for i := len(history.Undone) - 1; i >= 0; i-- {
+			c := history.Undone[i]
+			fmt.Printf("redo  %s  %s\n", c.At.Format("15:04"), c.Action)
+		}
+		return nil
+	}
+
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	var c core.Change
+	if redo {
+		c, err = history.Redo(data)
+	} else {
+		c, err = history.Undo(data)
+	}
+	if err != nil {
+		return err
+	}
+	if err := a.store.Save(ctx, data); err != nil {
+		return err
+	}
+	if err := a.saveHistory(ctx, history); err != nil {
+		return err
+	}
+	if redo {
+		fmt.Printf("redid %q\n", c.Action)
+	} else {
+		fmt.Printf("undid %q from %s\n", c.Action, c.At.Format("15:04"))
+	}
+	return nil
+}
+
+// backup lists the backups of the data file or restores one:
+// tunesday backup list, tunesday backup restore [--yes] <number>.
+func (a *App) backup(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: tunesday backup list | tunesday backup restore [--yes] <number>")
+	if len(args) == 0 {
+		return usage
+	}
+	bs, ok := a.store.(storage.BackupStore)
+	if !ok {
+		return fmt.Errorf("this store keeps no backups")
+	}
+	backups, err := bs.Backups()
+	if err != nil {
+		return err
+	}
+	data, err := a.store.Load(ctx)
+	var de *storage.DecodeError
+	if errors.As(err, &de) {
+		// a backup is the way out of a broken file, so compare with nothing
+		fmt.Printf("the data file can't be read (%v), comparing the backups with empty data\n\n", err)
+		data, err = core.NewData(), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		if len(backups) == 0 {
+			fmt.Println("no backups yet")
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tSaved\tSize\tRestoring it would change")
+		for i, b := range backups {
+			summary := "nothing"
+			if old, err := bs.LoadBackup(ctx, b); err != nil {
+				summary = "unreadable: " + err.Error()
+			} else if diff := core.Diff(data, old); len(diff) > 0 {
+				summary = strings.Join(diff, "; ")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, b.Time.Format("2006-01-02 15:04:05"), size(b.Size), summary)
+		}
+		return tw.Flush()
+
+	case "restore":
+		fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+		yes := fs.Bool("yes", false, "restore without asking")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return usage
+		}
+		n, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || n < 1 || n > len(backups) {
+			return fmt.Errorf("no backup %q, see tunesday backup list", fs.Arg(0))
+		}
+		b := backups[n-1]
+		old, err := bs.LoadBackup(ctx, b)
+		if err != nil {
+			return err
+		}
+		diff := core.Diff(data, old)
+		if len(diff) == 0 {
+			fmt.Println("the backup holds the same data as now, nothing to restore")
+			return nil
+		}
+		fmt.Printf("Restoring the backup from %s changes:\n", b.Time.Format("2006-01-02 15:04:05"))
+		for _, line := range diff {
+			fmt.Println("  " + line)
+		}
+		if !*yes && !ask(os.Stdin, "Restore it? The current data is backed up first. [y/N] ") {
+			fmt.Println("nothing restored")
+			return nil
+		}
+		if err := bs.Restore(ctx, b); err != nil {
+			return err
+		}
+		fmt.Println("restored")
+		return nil
+	}
+	return usage
+}
+
+// doctor looks for mistakes in the data file, e.g. after editing it by hand,
+// and offers to repair them: tunesday doctor [--yes].
+func (a *App) doctor(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "repair without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var data *core.Data
+	var problems []core.Problem
+	var err error
+	if cs, ok := a.store.(storage.CheckStore); ok {
+		data, problems, err = cs.Check(ctx)
+	} else {
+		data, err = a.store.Load(ctx)
+	}
+	var de *storage.DecodeError
+	if errors.As(err, &de) {
+		fmt.Println(de)
+		fmt.Println(pointAt(de))
+		fmt.Println("\nthis can't be repaired automatically: fix it by hand or restore a backup (tunesday backup list)")
+		return errors.New("the data file can't be read")
+	}
+	if err != nil {
+		return err
+	}
+	problems = append(problems, data.Check(a.yt.NormalizeYouTubeID)...)
+	if len(problems) == 0 {
+		fmt.Println("no problems found")
+		return nil
+	}
+
+	fixable := 0
+	fmt.Printf("found %s:\n", count(len(problems), "problem"))
+	for _, p := range problems {
+		fmt.Println("  - " + p.String())
+		if p.Fix != "" {
+			fixable++
+		}
+	}
+	if fixable == 0 {
+		return errors.New("none of them can be repaired automatically")
+	}
+	question := fmt.Sprintf("Repair %s? The current file is backed up first. [y/N] ", count(fixable, "problem"))
+	if !*yes && !ask(os.Stdin, question) {
+		fmt.Println("nothing repaired")
+		return nil
+	}
+
+	history, err := a.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	_ = history.Record("doctor", data, time.Now())
+	data.Repair(a.yt.NormalizeYouTubeID)
+	if err := a.store.Save(ctx, data); err != nil {
+		return err
+	}
+	if err := a.saveHistory(ctx, history); err != nil {
+		return err
+	}
+	fmt.Printf("repaired %s\n", count(fixable, "problem"))
+	if _, ok := a.store.(storage.BackupStore); ok {
+		fmt.Println("the file as it was is backup 1 in tunesday backup list")
+	}
+	if left := len(problems) - fixable; left > 0 {
+		return fmt.Errorf("%s left to fix by hand", count(left, "problem"))
+	}
+	return nil
+}
+
+// count writes n things, e.g. "1 problem" or "2 problems".
+func count(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// export writes the data in a portable format:
+// tunesday export [--format csv|ndjson|archive] [--table tunes|participants] [--out file].
+func (a *App) export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", "csv, ndjson or archive (default from --out, else ndjson)")
+	table := fs.String("table", "tunes", "what to write as csv: tunes or participants")
+	outPath := fs.String("out", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f, err := transferFormat(*format, *outPath, transfer.NDJSON)
+	if err != nil {
+		return err
+	}
+	if err := transfer.Table(*table).Check(); err != nil {
+		return err
+	}
+
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	return writeOut(*outPath, func(out io.Writer) error {
+		return transfer.Export(out, data, f, transfer.Table(*table))
+	})
+}
+
+// importData merges data exported by tunesday, or a spreadsheet saved as
+// CSV, into the data; with --replace it takes the place of the data:
+// tunesday import [--format csv|ndjson|archive] [--replace] [--yes] <file>.
+func (a *App) importData(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "csv, ndjson or archive (default from the file name)")
+	replace := fs.Bool("replace", false, "replace all data instead of merging")
+	yes := fs.Bool("yes", false, "import without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tunesday import [--format csv|ndjson|archive] [--replace] [--yes] <file>")
+	}
+	path := fs.Arg(0)
+	f, err := transferFormat(*format, path, "")
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	in, err := transfer.Import(file, f)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for i := range in.Tunes {
+		t := &in.Tunes[i]
+		if t.ID != "" {
+			continue
+		}
+		if id, ok := a.yt.NormalizeYouTubeID(t.Link); ok {
+			t.ID, t.Provider = id, "youtube"
+		}
+	}
+
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	return a.applyImport(ctx, data, in, "import "+filepath.Base(path), *replace, *yes)
+}
+
+// applyImport merges in into data, or replaces data with it, after showing
+// what changes and asking unless yes is set. The import can be undone as
+// action.
+func (a *App) applyImport(ctx context.Context, data, in *core.Data, action string, replace, yes bool) error {
+	next := in
+	if !replace {
+		next = data.Clone()
+		if r := next.Merge(in); r.Duplicates > 0 {
+			fmt.Printf("%s already there, left out\n", count(r.Duplicates, "tune"))
+		}
+	}
+	diff := core.Diff(data, next)
+	if len(diff) == 0 {
+		fmt.Println("nothing new to import")
+		return nil
+	}
+	if replace {
+		fmt.Println("Replacing the data with the import changes:")
+	} else {
+		fmt.Println("Importing changes:")
+	}
+	for _, line := range diff {
+		fmt.Println("  " + line)
+	}
+	if !yes && !ask(os.Stdin, "Import? The current data is backed up first. [y/N] ") {
+		fmt.Println("nothing imported")
+		return nil
+	}
+
+	history, err := a.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	_ = history.Record(action, data, time.Now())
+	if err := a.store.Save(ctx, next); err != nil {
+		return err
+	}
+	if err := a.saveHistory(ctx, history); err != nil {
+		return err
+	}
+	fmt.Println("imported")
+	return nil
+}
+
+// transferFormat picks the format called name, else the one of the file
+// name, else def.
+func transferFormat(name, path string, def transfer.Format) (transfer.Format, error) {
+	if name != "" {
+		return transfer.ParseFormat(name)
+	}
+	if f, ok := transfer.FormatOf(path); ok {
+		return f, nil
+	}
+	if def == "" {
+		return "", fmt.Errorf("can't tell the format of %s, use --format csv, ndjson or archive", path)
+	}
+	return def, nil
+}
+
+// ask asks a yes/no question on the terminal; anything but yes means no.
+func ask(in io.Reader, question string) bool {
+	fmt.Print(question)
+	var answer string
+	fmt.Fscanln(in, &answer)
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// size formats a file size in bytes.
+func size(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
+
+// wrapped prints or writes the yearly report.
+func (a *App) wrapped(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("wrapped", flag.ContinueOnError)
+	year := fs.Int("year", time.Now().Year(), "calendar year to summarize")
+	format := fs.String("format", "", "ansi, md or html (default from --out, else ansi)")
+	outPath := fs.String("out", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*outPath)) {
+		case ".md", ".markdown":
+			*format = "md"
+		case ".html", ".htm":
+			*format = "html"
+		default:
+			*format = "ansi"
+		}
+	}
+
+	var write func(w *stats.Wrapped, out io.Writer) error
+	switch *format {
+	case "ansi":
+		write = func(w *stats.Wrapped, out io.Writer) error {
+			// files get the report without colours
+			var styles stats.ANSIStyles
+			if *outPath == "" {
+				styles = termui.ReportStyles()
+			}
+			w.WriteANSI(out, termui.TermWidth(), styles)
+			return nil
+		}
+	case "md", "markdown":
+		write = (*stats.Wrapped).WriteMarkdown
+	case "html":
+		write = (*stats.Wrapped).WriteHTML
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	w := stats.NewWrapped(data, *year)
+	return writeOut(*outPath, func(out io.Writer) error { return write(w, out) })
+}
+
+// writeOut runs write on stdout, or on the file at path when there is one.
+// A file that couldn't be written completely is removed again.
+func writeOut(path string, write func(out io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	fmt.Println("Wrote", path)
+	return nil
+}
+
+// skins previews the built-in skins and those defined in the config file.
+func skins() error {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	for _, name := range termui.SkinNames() {
+		termui.PreviewSkin(termui.Skins[name])
+		fmt.Println()
+	}
+	names := make([]string, 0, len(cfg.Skins))
+	for name := range cfg.Skins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s, err := termui.SkinFromConfig(name, cfg.Skins[name])
+		if err != nil {
+			return err
+		}
+		termui.PreviewSkin(s)
+		fmt.Println()
+	}
+	fmt.Printf("Pick one with \"skin\" in %s or TUNESDAY_SKIN.\n", config.Path())
+	return nil
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"tunesday/internal/config"
+	"tunesday/internal/storage"
+	"tunesday/internal/termui"
+)
+
+// key shows whether the data file is encrypted and changes its passphrase:
+// tunesday key [status], tunesday key rotate [--new-key-file file] [--yes] or
+// tunesday key remove [--yes].
+func (a *App) key(ctx context.Context, args []string) error {
+	ks, ok := a.store.(storage.KeyStore)
+	if cs, isCodec := a.store.(storage.CodecStore); !ok && isCodec {
+		// not encrypted so far
+		ks, ok = storage.Encrypted(cs), true
+	}
+	if !ok {
+		return errors.New("this store can't be encrypted")
+	}
+	sub := "status"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("key "+sub, flag.ContinueOnError)
+	newKeyFile := fs.String("new-key-file", "", "read the new passphrase from this file instead of asking for it")
+	yes := fs.Bool("yes", false, "don't ask")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "status":
+		enc, err := ks.Encrypted()
+		if err != nil {
+			return err
+		}
+		if enc {
+			fmt.Println("the data file is encrypted (AES-256-GCM, with a key derived from the passphrase by PBKDF2-SHA256)")
+		} else {
+			fmt.Println(`the data file is not encrypted; "tunesday key rotate" encrypts it`)
+		}
+		return nil
+	case "rotate":
+		// everything has to open with the current passphrase first
+		if _, err := a.load(ctx); err != nil {
+			return err
+		}
+		passphrase, err := newPassphrase(*newKeyFile)
+		if err != nil {
+			return err
+		}
+		k, err := storage.NewKey(passphrase)
+		if err != nil {
+			return err
+		}
+		if !*yes && !ask(os.Stdin, "Encrypt the data file, its history and its backups with the new passphrase? [y/N] ") {
+			fmt.Println("nothing changed")
+			return nil
+		}
+		if err := ks.Rekey(ctx, k); err != nil {
+			return err
+		}
+		fmt.Println("encrypted with the new passphrase; give it from now on with TUNESDAY_PASSPHRASE or the key file in the config")
+		return nil
+	case "remove":
+		if _, err := a.load(ctx); err != nil {
+			return err
+		}
+		if !*yes && !ask(os.Stdin, "Store the data file, its history and its backups unencrypted? [y/N] ") {
+			fmt.Println("nothing changed")
+			return nil
+		}
+		if err := ks.Rekey(ctx, nil); err != nil {
+			return err
+		}
+		fmt.Println("the data is no longer encrypted; remove the passphrase from TUNESDAY_PASSPHRASE and the config, or the next save encrypts it again")
+		return nil
+	}
+	return fmt.Errorf("usage: tunesday key [status | rotate [--new-key-file file] [--yes] | remove [--yes]]")
+}
+
+// newPassphrase returns the passphrase to rotate to: from keyFile,
+// $TUNESDAY_NEW_PASSPHRASE, or typed in twice.
+func newPassphrase(keyFile string) (string, error) {
+	if keyFile != "" {
+		return config.ReadKeyFile(keyFile)
+	}
+	if p := os.Getenv("TUNESDAY_NEW_PASSPHRASE"); p != "" {
+		return p, nil
+	}
+	p, err := termui.ReadSecret("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	again, err := termui.ReadSecret("Once more: ")
+	if err != nil {
+		return "", err
+	}
+	if p != again {
+		return "", errors.New("the passphrases don't match")
+	}
+	return p, nil
+}
@@ -0,0 +1,279 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tunesday/internal/core"
+	"tunesday/internal/playlist"
+	"tunesday/internal/transfer"
+)
+
+// importLinks adds the tunes found in a file of links, a chat export or a
+// YouTube playlist, attributed to participants and with the date they were
+// posted. Playlists and feeds don't tell when a video was picked, so their
+// tunes get the --date, or none: tunesday import-links [--map file]
+// [--as name] [--date day] [--jobs n] [--yes] <file or URL>.
+func (a *App) importLinks(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import-links", flag.ContinueOnError)
+	mapPath := fs.String("map", "", `file mapping chat names or IDs to participants, a "name = participant" per line`)
+	as := fs.String("as", "", "attribute all tunes to this participant")
+	date := fs.String("date", "", "the day tunes without a date of their own were picked, like 2006-01-02")
+	jobs := fs.Int("jobs", 8, "titles to fetch at the same time")
+	yes := fs.Bool("yes", false, "import without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tunesday import-links [--map file] [--as participant] [--date day] [--jobs n] [--yes] <file, playlist or feed URL>")
+	}
+	var picked time.Time
+	if *date != "" {
+		var err error
+		if picked, err = time.ParseInLocation("2006-01-02", *date, time.Local); err != nil {
+			return fmt.Errorf("--date: want a date like 2006-01-02")
+		}
+	}
+	src := fs.Arg(0)
+	mapping, err := readMapping(*mapPath)
+	if err != nil {
+		return err
+	}
+	data, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	links, err := a.readLinks(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	who := func(l transfer.Link) string {
+		if *as != "" {
+			return *as
+		}
+		for _, key := range []string{l.AuthorID, l.Author} {
+			if name, ok := mapping[strings.ToLower(key)]; key != "" && ok {
+				return name
+			}
+		}
+		if p := data.ParticipantByName(l.Author); l.Author != "" && p != nil {
+			return p.Name
+		}
+		return ""
+	}
+	known := make(map[string]bool, len(data.Tunes))
+	for _, t := range data.Tunes {
+		known[t.ID] = true
+	}
+
+	in := core.NewData()
+	index := make(map[string]int) // video ID -> tune in in
+	var old, foreign int
+	unattributed := make(map[string]int)
+	for _, l := range links {
+		link := playlist.StripTrackingParams(l.URL)
+		id, ok := a.yt.NormalizeYouTubeID(link)
+		switch {
+		case !ok:
+			foreign++
+			continue
+		case known[id]:
+			old++
+			continue
+		}
+		t := core.Tune{Name: l.Title, Link: link, ID: id, Provider: "youtube", Channel: l.Channel,
+			Seconds: int(l.Duration.Seconds()), AddedAt: l.At}
+		if t.AddedAt.IsZero() {
+			t.AddedAt = picked
+		}
+		if name := who(l); name != "" {
+			p := in.ParticipantByName(name)
+			if p == nil {
+				p, _ = in.AddParticipant(name)
+				p.JoinedAt = time.Time{}
+			}
+			t.ParticipantID = p.ID
+		}
+		if i, seen := index[id]; seen {
+			// posted again: the first post is the pick, the rest are replies
+			if !l.At.IsZero() && l.At.Before(in.Tunes[i].AddedAt) {
+				in.Tunes[i] = t
+			}
+			continue
+		}
+		if t.ParticipantID == "" && l.Author != "" {
+			unattributed[l.Author]++
+		}
+		index[id] = len(in.Tunes)
+		in.Tunes = append(in.Tunes, t)
+	}
+
+	fmt.Printf("found %s: %d new, %d already in the list, %d not YouTube videos\n",
+		count(len(links), "link"), len(in.Tunes), old, foreign)
+	if len(unattributed) > 0 {
+		names := make([]string, 0, len(unattributed))
+		for name, n := range unattributed {
+			names = append(names, fmt.Sprintf("%s (%d)", name, n))
+		}
+		sort.Strings(names)
+		fmt.Println("not attributed, add them to --map to change that: " + strings.Join(names, ", "))
+	}
+	if failed := a.fetchTitles(ctx, in.Tunes, *jobs); failed > 0 {
+		fmt.Printf("%s couldn't be fetched, those tunes are named after their link\n", count(failed, "title"))
+	}
+	return a.applyImport(ctx, data, in, "import links from "+filepath.Base(src), false, *yes)
+}
+
+// readLinks reads the links from a file, a playlist feed or a YouTube
+// playlist.
+func (a *App) readLinks(ctx context.Context, src string) ([]transfer.Link, error) {
+	u, err := url.Parse(src)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		links, err := transfer.ReadLinks(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		return links, nil
+	}
+
+	if u.Query().Get("list") != "" && !strings.Contains(u.Path, "/feeds/") {
+		pp, ok := a.yt.(playlist.PlaylistProvider)
+		if !ok {
+			return nil, fmt.Errorf("can't read playlists, use the playlist's feed instead")
+		}
+		entries, err := pp.Playlist(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		links := make([]transfer.Link, 0, len(entries))
+		for _, e := range entries {
+			links = append(links, transfer.Link{URL: "https://www.youtube.com/watch?v=" + e.ID, Title: e.Title, Channel: e.Channel, Duration: e.Duration})
+		}
+		return links, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	links, err := transfer.ReadLinks(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	return links, nil
+}
+
+// readMapping reads a file of "chat name or ID = participant" lines. Keys
+// are matched regardless of case.
+func readMapping(path string) (map[string]string, error) {
+	m := make(map[string]string)
+	if path == "" {
+		return m, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, name, ok := strings.Cut(text, "=")
+		key, name = strings.TrimSpace(key), strings.TrimSpace(name)
+		if !ok || key == "" || name == "" {
+			return nil, fmt.Errorf("%s:%d: want \"chat name = participant\"", path, line)
+		}
+		m[strings.ToLower(key)] = name
+	}
+	return m, sc.Err()
+}
+
+// fetchTitles fills in the titles of the tunes that have none, fetching up
+// to jobs at a time. Tunes whose title can't be fetched are named after their
+// link; it returns how many those are.
+func (a *App) fetchTitles(ctx context.Context, tunes []core.Tune, jobs int) (failed int) {
+	var todo []int
+	for i := range tunes {
+		if tunes[i].Name == "" {
+			todo = append(todo, i)
+		}
+	}
+	if len(todo) == 0 {
+		return 0
+	}
+	fmt.Printf("fetching %s...\n", count(len(todo), "title"))
+
+	work := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, min(jobs, len(todo))); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				// every worker has tunes of its own, only the output is shared
+				t := &tunes[i]
+				if err := a.fetchInfo(ctx, t); err != nil {
+					t.Name = t.Link
+					mu.Lock()
+					fmt.Printf("  %s: %v\n", t.Link, err)
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, i := range todo {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return failed
+}
+
+func (a *App) fetchInfo(ctx context.Context, t *core.Tune) error {
+	if ip, ok := a.yt.(playlist.InfoProvider); ok {
+		info, err := ip.FetchInfo(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		t.Name, t.Seconds = info.Title, int(info.Duration.Seconds())
+		if t.Channel == "" {
+			t.Channel = info.Channel
+		}
+		return nil
+	}
+	title, err := a.yt.FetchTitle(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	t.Name = title
+	return nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"tunesday/internal/server"
+)
+
+// serve offers the data as a JSON REST API until interrupted:
+// tunesday serve [--addr host:port] [--token token].
+func (a *App) serve(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on; :8080 for everyone on the network")
+	token := fs.String("token", os.Getenv("TUNESDAY_API_TOKEN"), "token every request has to carry as \"Authorization: Bearer <token>\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: tunesday serve [--addr host:port] [--token token]")
+	}
+
+	api := server.New(a.store, a.yt)
+	api.SetToken(*token)
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: api, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Printf("serving the API on http://%s/api/, described at /api/openapi.json; Ctrl-C stops\n", ln.Addr())
+	if host, _, _ := net.SplitHostPort(*addr); *token == "" && !isLoopback(host) {
+		fmt.Println("warning: anyone who can reach this address can change the data, set --token or TUNESDAY_API_TOKEN")
+	}
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
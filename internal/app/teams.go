@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+// teamViews shows all teams side by side: tunesday teams for an overview,
+// tunesday teams tunes [--since date] for the tunes of all teams this week.
+func (a *App) teamViews(ctx context.Context, args []string) error {
+	if len(a.teams) == 0 {
+		return errors.New(`no teams are set up, add them to the config file under "teams"`)
+	}
+	if len(args) == 0 {
+		return a.teamOverview(ctx)
+	}
+	if args[0] != "tunes" {
+		return fmt.Errorf("usage: tunesday teams [tunes [--since 2006-01-02]]")
+	}
+
+	fs := flag.NewFlagSet("teams tunes", flag.ContinueOnError)
+	since := fs.String("since", "", "show tunes added from this day on (default: this week's Monday)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	from := startOfWeek(time.Now())
+	if *since != "" {
+		var err error
+		if from, err = time.ParseInLocation("2006-01-02", *since, time.Local); err != nil {
+			return fmt.Errorf("--since: want a date like 2006-01-02")
+		}
+	}
+
+	type row struct {
+		team string
+		by   string
+		core.Tune
+	}
+	var rows []row
+	var unread []string
+	for _, t := range a.teams {
+		data, err := a.loadTeam(ctx, t)
+		if err != nil {
+			unread = append(unread, fmt.Sprintf("%s can't be read: %v", t.Name, err))
+			continue
+		}
+		for _, tune := range data.Tunes {
+			if !tune.AddedAt.Before(from) {
+				rows = append(rows, row{t.Name, data.ParticipantName(tune.ParticipantID), tune})
+			}
+		}
+	}
+	defer func() {
+		for _, u := range unread {
+			fmt.Println(u)
+		}
+	}()
+	if len(rows) == 0 {
+		fmt.Printf("no tunes since %s\n", from.Format("Mon 2006-01-02"))
+		return nil
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].AddedAt.Before(rows[j].AddedAt) })
+
+	fmt.Printf("Tunes of all teams since %s\n\n", from.Format("Mon 2006-01-02"))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Day\tTeam\tBy\tTune\tLink")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.AddedAt.Format("Mon 01-02"), r.team, r.by, r.Name, r.Link)
+	}
+	return tw.Flush()
+}
+
+// teamOverview lists the teams with their size and activity.
+func (a *App) teamOverview(ctx context.Context) error {
+	week := startOfWeek(time.Now())
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tTeam\tParticipants\tTunes\tThis week\tLast session")
+	for _, t := range a.teams {
+		current := ""
+		if t.Name == a.team {
+			current = "*"
+		}
+		data, err := a.loadTeam(ctx, t)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t%s\tcan't be read: %v\n", current, t.Name, err)
+			continue
+		}
+		thisWeek := 0
+		for _, tune := range data.Tunes {
+			if !tune.AddedAt.Before(week) {
+				thisWeek++
+			}
+		}
+		last := "never"
+		for _, s := range data.Sessions {
+			if day := s.Date.Format("2006-01-02"); last == "never" || day > last {
+				last = day
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n", current, t.Name, len(data.CurrentParticipants()), len(data.Tunes), thisWeek, last)
+	}
+	return tw.Flush()
+}
+
+// loadTeam opens the store of t, if need be, and loads it.
+func (a *App) loadTeam(ctx context.Context, t Team) (*core.Data, error) {
+	store, err := t.open()
+	if err != nil {
+		return nil, err
+	}
+	return store.Load(ctx)
+}
+
+// startOfWeek returns the Monday of the week of t, at midnight.
+func startOfWeek(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -days).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
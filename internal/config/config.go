@@ -0,0 +1,181 @@
+// Package config reads the user's settings file.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Config is the user's settings. Everything is optional.
+type Config struct {
+	Skin     string          `json:"skin,omitempty"`     // name of the skin to use
+	Skins    map[string]Skin `json:"skins,omitempty"`    // skins defined by the user
+	Language string          `json:"language,omitempty"` // "en" or "de"; taken from LANG when empty
+	Backups  Backups         `json:"backups,omitempty"`
+
+	Encryption Encryption `json:"encryption,omitempty"`
+
+	Teams       map[string]Team `json:"teams,omitempty"`        // workspaces by name
+	DefaultTeam string          `json:"default_team,omitempty"` // team used when none is picked
+}
+
+// Team is a workspace: a team with its own data file, and with that its own
+// participants, tunes, history and backups, plus settings that replace the
+// general ones.
+type Team struct {
+	Data     string  `json:"data,omitempty"` // data file, tunesday.<team>.json by default
+	Skin     string  `json:"skin,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Backups  Backups `json:"backups,omitempty"`
+
+	Encryption Encryption `json:"encryption,omitempty"`
+}
+
+// Backups says how many backups of the data file to keep: the last Keep
+// versions and the newest version of each of the last Daily days and Weekly
+// weeks. Zero keeps the default of 10, 7 and 8, and -1 turns that kind of
+// backup off.
+type Backups struct {
+	Keep   int `json:"keep,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
+// Encryption says where the passphrase for the data file comes from. The
+// data is encrypted when there is one: $TUNESDAY_PASSPHRASE, or else the
+// first line of KeyFile.
+type Encryption struct {
+	KeyFile string `json:"key_file,omitempty"`
+}
+
+// Passphrase returns the passphrase for the data file, "" when the data
+// isn't encrypted.
+func (e Encryption) Passphrase() (string, error) {
+	if p := os.Getenv("TUNESDAY_PASSPHRASE"); p != "" {
+		return p, nil
+	}
+	if e.KeyFile == "" {
+		return "", nil
+	}
+	return ReadKeyFile(e.KeyFile)
+}
+
+// ReadKeyFile returns the passphrase in a key file: its first line.
+func ReadKeyFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("key file: %w", err)
+	}
+	line, _, _ := strings.Cut(string(b), "\n")
+	if line = strings.TrimRight(line, "\r"); line == "" {
+		return "", fmt.Errorf("key file %s is empty", path)
+	}
+	return line, nil
+}
+
+// TeamNames returns the names of the teams, sorted.
+func (c Config) TeamNames() []string {
+	names := make([]string, 0, len(c.Teams))
+	for name := range c.Teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForTeam returns the settings for team: the general ones with those of the
+// team in their place.
+func (c Config) ForTeam(team string) (Config, error) {
+	t, ok := c.Teams[team]
+	if !ok {
+		if len(c.Teams) == 0 {
+			return c, fmt.Errorf("unknown team %q, no teams are set up in %s", team, Path())
+		}
+		return c, fmt.Errorf("unknown team %q, the teams are %s", team, strings.Join(c.TeamNames(), ", "))
+	}
+	if t.Skin != "" {
+		c.Skin = t.Skin
+	}
+	if t.Language != "" {
+		c.Language = t.Language
+	}
+	if t.Backups != (Backups{}) {
+		c.Backups = t.Backups
+	}
+	if t.Encryption != (Encryption{}) {
+		c.Encryption = t.Encryption
+	}
+	return c, nil
+}
+
+// DataFile returns the data file of team.
+func (c Config) DataFile(team string) string {
+	if f := c.Teams[team].Data; f != "" {
+		return f
+	}
+	return "tunesday." + team + ".json"
+}
+
+// Skin is a user-defined skin: a built-in skin with some parts replaced.
+// Styles are written like "bold cyan" or "bold #ffffff on #0277bd".
+type Skin struct {
+	Base     string `json:"base,omitempty"` // built-in skin to start from, "classic" by default
+	Logo     string `json:"logo,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Accent   string `json:"accent,omitempty"`
+	Selected string `json:"selected,omitempty"`
+	Banner   string `json:"banner,omitempty"`
+	Muted    string `json:"muted,omitempty"`
+	Cursor   string `json:"cursor,omitempty"`
+	Header   string `json:"header,omitempty"` // header font
+}
+
+// teamName is what team names look like; they become part of file names.
+var teamName = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// Path returns the settings file: $TUNESDAY_CONFIG, or tunesday/config.json
+// in the user's configuration directory.
+func Path() string {
+	if p := os.Getenv("TUNESDAY_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tunesday", "config.json")
+}
+
+// Load reads the settings at path. A missing file gives the zero Config.
+func Load(path string) (Config, error) {
+	var c Config
+	if path == "" {
+		return c, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("config %s: %w", path, err)
+	}
+	for name := range c.Teams {
+		if !teamName.MatchString(name) {
+			return c, fmt.Errorf("config %s: team name %q may only have letters, digits, - and _", path, name)
+		}
+	}
+	if _, ok := c.Teams[c.DefaultTeam]; c.DefaultTeam != "" && !ok {
+		return c, fmt.Errorf("config %s: default_team %q is not one of the teams", path, c.DefaultTeam)
+	}
+	return c, nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || c.Skin != "" {
+		t.Fatalf("missing file: %+v, %v", c, err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	raw := `{"skin": "mine", "language": "de", "backups": {"keep": 3}, "skins": {"mine": {"base": "ocean", "accent": "bold #ff8800", "cursor": "» "}}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Skin != "mine" || c.Language != "de" || c.Backups.Keep != 3 || c.Skins["mine"].Base != "ocean" || c.Skins["mine"].Cursor != "» " {
+		t.Fatalf("loaded %+v", c)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected an error for broken JSON")
+	}
+}
+
+func TestPathFromEnv(t *testing.T) {
+	t.Setenv("TUNESDAY_CONFIG", "/tmp/x.json")
+	if got := Path(); got != "/tmp/x.json" {
+		t.Fatalf("Path() = %q", got)
+	}
+}
+
+func TestTeams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	raw := `{"skin": "classic", "language": "en", "backups": {"keep": 3}, "default_team": "web",
+		"teams": {"web": {"skin": "ocean", "backups": {"daily": 2}}, "data": {"data": "/srv/data.json", "language": "de"}}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.TeamNames(); len(got) != 2 || got[0] != "data" || got[1] != "web" {
+		t.Fatalf("TeamNames() = %q", got)
+	}
+	web, err := c.ForTeam("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if web.Skin != "ocean" || web.Language != "en" || web.Backups != (Backups{Daily: 2}) {
+		t.Errorf("web settings = %+v", web)
+	}
+	if got := c.DataFile("web"); got != "tunesday.web.json" {
+		t.Errorf("web data file = %q", got)
+	}
+	if got := c.DataFile("data"); got != "/srv/data.json" {
+		t.Errorf("data data file = %q", got)
+	}
+	if _, err := c.ForTeam("ops"); err == nil || err.Error() != `unknown team "ops", the teams are data, web` {
+		t.Errorf("ForTeam(ops) = %v", err)
+	}
+
+	for _, bad := range []string{`{"teams": {"a b": {}}}`, `{"teams": {"web": {}}, "default_team": "ops"}`} {
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%s) accepted it", bad)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(key, []byte("from the file\r\nignored\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	e := Encryption{KeyFile: key}
+	t.Setenv("TUNESDAY_PASSPHRASE", "")
+	if p, err := e.Passphrase(); p != "from the file" || err != nil {
+		t.Errorf("Passphrase = %q, %v", p, err)
+	}
+	if p, err := (Encryption{}).Passphrase(); p != "" || err != nil {
+		t.Errorf("without a key file: %q, %v", p, err)
+	}
+	t.Setenv("TUNESDAY_PASSPHRASE", "from the env")
+	if p, _ := e.Passphrase(); p != "from the env" {
+		t.Errorf("Passphrase = %q, want the one from the environment", p)
+	}
+}
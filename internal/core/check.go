@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Problem is an inconsistency in the data, e.g. from editing the file by
+// hand. Fix says how Repair deals with it and is empty when it can't.
+type Problem struct {
+	Text string
+	Fix  string
+}
+
+func (p Problem) String() string {
+	if p.Fix == "" {
+		return p.Text
+	}
+	return p.Text + " (fix: " + p.Fix + ")"
+}
+
+// Check finds inconsistencies in the data without changing it. videoID
+// extracts the YouTube video ID from a link, like the title providers do.
+func (d *Data) Check(videoID func(link string) (string, bool)) []Problem {
+	return d.check(videoID, false)
+}
+
+// Repair fixes what Check finds, where it can, and returns all problems.
+func (d *Data) Repair(videoID func(link string) (string, bool)) []Problem {
+	return d.check(videoID, true)
+}
+
+func (d *Data) check(videoID func(string) (string, bool), fix bool) []Problem {
+	var out []Problem
+	report := func(fixText string, format string, args ...any) {
+		out = append(out, Problem{Text: fmt.Sprintf(format, args...), Fix: fixText})
+	}
+
+	// participants: names must be there and unique regardless of case
+	byName := make(map[string]*Participant)
+	for _, p := range d.SortedParticipants() {
+		if strings.TrimSpace(p.Name) == "" {
+			name := d.freeName("Participant " + shortID(p.ID))
+			report(fmt.Sprintf("name them %q", name), "participant %s has no name", p.ID)
+			if !fix {
+				continue
+			}
+			p.Name = name
+		}
+		key := strings.ToLower(strings.TrimSpace(p.Name))
+		if first := byName[key]; first != nil {
+			name := d.freeName(p.Name)
+			report(fmt.Sprintf("rename the second to %q", name), "participants %q and %q have the same name", first.Name, p.Name)
+			if fix {
+				p.Name = name
+				key = strings.ToLower(name)
+			}
+		}
+		byName[key] = p
+	}
+
+	// draws of participants that don't exist
+	for _, s := range d.Sessions {
+		var kept []*Draw
+		for _, dr := range s.Draws {
+			if d.Participants[dr.ParticipantID] == nil {
+				report("drop the draw", "session %s has a draw for unknown participant %q", s.Date.Format("2006-01-02"), dr.ParticipantID)
+				continue
+			}
+			kept = append(kept, dr)
+		}
+		if fix {
+			s.Draws = kept
+		}
+	}
+
+	for i := range d.Tunes {
+		t := &d.Tunes[i]
+		name := t.Name
+		if name == "" {
+			name = t.Link
+		}
+		if t.ID == "" && videoID != nil {
+			if id, ok := videoID(t.Link); ok {
+				report(fmt.Sprintf("set it to %s", id), "tune %d %q has no video ID", i+1, name)
+				if fix {
+					t.ID = id
+				}
+			} else if t.Provider == "youtube" {
+				report("", "tune %d %q is a YouTube tune without a usable link", i+1, name)
+			}
+		}
+		if t.ParticipantID != "" && d.Participants[t.ParticipantID] == nil {
+			report("forget who brought it", "tune %d %q was brought by unknown participant %q", i+1, name, t.ParticipantID)
+			if fix {
+				t.ParticipantID = ""
+			}
+		}
+		voters := make([]string, 0, len(t.Votes))
+		for id := range t.Votes {
+			voters = append(voters, id)
+		}
+		sort.Strings(voters)
+		for _, id := range voters {
+			switch stars := t.Votes[id]; {
+			case d.Participants[id] == nil:
+				report("drop the vote", "tune %d %q has a vote from unknown participant %q", i+1, name, id)
+			case stars < 1 || stars > 5:
+				report("drop the vote", "tune %d %q has a vote of %d stars by %s", i+1, name, stars, d.Participants[id].Name)
+			default:
+				continue
+			}
+			if fix {
+				delete(t.Votes, id)
+			}
+		}
+	}
+	return out
+}
+
+// freeName returns name, or name with a number added when a participant
+// already has it.
+func (d *Data) freeName(name string) string {
+	if d.ParticipantByName(name) == nil {
+		return name
+	}
+	for n := 2; ; n++ {
+		if try := fmt.Sprintf("%s (%d)", name, n); d.ParticipantByName(try) == nil {
+			return try
+		}
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 6 {
+		return id[:6]
+	}
+	return id
+}
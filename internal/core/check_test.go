@@ -0,0 +1,76 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func youtubeID(link string) (string, bool) {
+	id, ok := strings.CutPrefix(link, "https://youtu.be/")
+	return id, ok
+}
+
+func brokenData() *Data {
+	d := NewData()
+	d.Participants["a"] = &Participant{ID: "a", Name: "Ann"}
+	d.Participants["b"] = &Participant{ID: "b", Name: "ann"}
+	d.Participants["c"] = &Participant{ID: "c", Name: " "}
+	d.Sessions = []*Session{{ID: "s", Draws: []*Draw{{ParticipantID: "a"}, {ParticipantID: "gone"}}}}
+	d.Tunes = []Tune{
+		{Name: "One", Link: "https://youtu.be/one", Provider: "youtube", ParticipantID: "gone",
+			Votes: map[string]int{"a": 4, "b": 9, "gone": 5}},
+		{Name: "Two", Link: "https://example.com/two", Provider: "youtube"},
+		{Name: "Three", Link: "https://example.com/three", Provider: "manual"},
+	}
+	return d
+}
+
+func TestCheckFindsProblems(t *testing.T) {
+	d := brokenData()
+	var got []string
+	for _, p := range d.Check(youtubeID) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		`participant c has no name (fix: name them "Participant c")`,
+		`participants "Ann" and "ann" have the same name (fix: rename the second to "ann (2)")`,
+		`session 0001-01-01 has a draw for unknown participant "gone" (fix: drop the draw)`,
+		`tune 1 "One" has no video ID (fix: set it to one)`,
+		`tune 1 "One" was brought by unknown participant "gone" (fix: forget who brought it)`,
+		`tune 1 "One" has a vote of 9 stars by ann (fix: drop the vote)`,
+		`tune 1 "One" has a vote from unknown participant "gone" (fix: drop the vote)`,
+		`tune 2 "Two" is a YouTube tune without a usable link`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Check =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !reflect.DeepEqual(d, brokenData()) {
+		t.Fatal("Check changed the data")
+	}
+}
+
+func TestRepairFixesProblems(t *testing.T) {
+	d := brokenData()
+	d.Repair(youtubeID)
+	if got := d.Participants["c"].Name; got != "Participant c" {
+		t.Errorf("unnamed participant = %q", got)
+	}
+	if got := d.Participants["b"].Name; got != "ann (2)" {
+		t.Errorf("duplicate = %q", got)
+	}
+	if n := len(d.Sessions[0].Draws); n != 1 {
+		t.Errorf("draws = %d, want 1", n)
+	}
+	tune := d.Tunes[0]
+	if tune.ID != "one" || tune.ParticipantID != "" || !reflect.DeepEqual(tune.Votes, map[string]int{"a": 4}) {
+		t.Errorf("tune = %+v", tune)
+	}
+	var left []string
+	for _, p := range d.Check(youtubeID) {
+		left = append(left, p.String())
+	}
+	if want := []string{`tune 2 "Two" is a YouTube tune without a usable link`}; !reflect.DeepEqual(left, want) {
+		t.Errorf("after Repair, Check = %q, want %q", left, want)
+	}
+}
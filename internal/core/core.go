@@ -2,21 +2,40 @@ package core
 
 import "time"
 
-// Data holds participants and tunes.
+// CurrentVersion is the data file format written by this build.
+// Older files are upgraded when they are decoded. Version 2 keeps
+// participants as records; version 3 adds sessions with their draws, the
+// theme catalogue, and votes, tags, themes, channels and lengths of tunes.
+// It goes up with every change to the format, so that older builds refuse
+// files whose fields they would drop.
+const CurrentVersion = 3
+
+// Data holds participants, the history of draws and tunes.
 type Data struct {
-    Participants map[string]int  `json:"participants"`       // name -> tunes count
-    Disabled     map[string]bool `json:"disabled,omitempty"` // name -> true if deactivated
-    Tunes        []Tune          `json:"tunes"`
+    Version      int                     `json:"version"`
+    Participants map[string]*Participant `json:"participants"` // id -> participant
+    Sessions     []*Session              `json:"sessions,omitempty"`
+    Themes       []*Theme                `json:"themes,omitempty"` // theme catalogue
+    Tunes        []Tune                  `json:"tunes"`
 }
 
 // Tune represents a single YouTube tune entry.
 type Tune struct {
-    Name     string    `json:"name"` // video title
-    Link     string    `json:"link"` // original YouTube URL
-    ID       string    `json:"id"`   // normalized YouTube video ID
-    Provider string    `json:"provider"`
-    AddedAt  time.Time `json:"added_at,omitempty"`
+    Name          string         `json:"name"` // video title
+    Link          string         `json:"link"` // original YouTube URL
+    ID            string         `json:"id"`   // normalized YouTube video ID
+    Provider      string         `json:"provider"`
+    Channel       string         `json:"channel,omitempty"` // uploader, usually the artist
+    Seconds       int            `json:"seconds,omitempty"` // video length
+    ParticipantID string         `json:"participant_id,omitempty"` // who brought the tune
+    SessionID     string         `json:"session_id,omitempty"`     // session whose draw it answered
+    Theme         string         `json:"theme,omitempty"`          // theme of the round it was picked for
+    Votes         map[string]int `json:"votes,omitempty"`          // participant id -> stars (1-5); thumbs up and down are kept as 5 and 1 stars
+    Tags          []string       `json:"tags,omitempty"`           // free-form labels, e.g. "cover"
+    AddedAt       time.Time      `json:"added_at,omitempty"`
 }
 
 // NewData creates an empty Data structure with initialized maps.
-func NewData() *Data { return &Data{Participants: make(map[string]int)} }
+func NewData() *Data {
+    return &Data{Version: CurrentVersion, Participants: make(map[string]*Participant)}
+}
@@ -20,7 +20,7 @@ func TestNewDataInitializesParticipants(t *testing.T) {
 
 func TestDataJSONRoundTrip(t *testing.T) {
 	d := &Data{
-		Participants: map[string]int{"Ann": 2},
+		Participants: map[string]*Participant{"p1": {ID: "p1", Name: "Ann", LegacyDraws: 2}},
 		Tunes: []Tune{
 			{
 				Name:     "Never Gonna Give You Up",
@@ -44,7 +44,7 @@ func TestDataJSONRoundTrip(t *testing.T) {
 	if err := json.Unmarshal(b, &out); err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
-	if out.Participants["Ann"] != 2 {
+	if p := out.Participants["p1"]; p == nil || p.Name != "Ann" || p.LegacyDraws != 2 {
 		t.Fatalf("unexpected participants after round trip: %+v", out)
 	}
 	if len(out.Tunes) != 2 {
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff summarizes how to differs from from, one line per kind of record,
+// e.g. "tunes: 2 added, 1 changed". It is empty when nothing changed.
+func Diff(from, to *Data) []string {
+	var out []string
+	add := func(kind string, parts []string) {
+		if len(parts) > 0 {
+			out = append(out, kind+": "+strings.Join(parts, ", "))
+		}
+	}
+
+	var ps []string
+	var added, removed, renamed, archived, restored, toggled []string
+	for id, p := range to.Participants {
+		old := from.Participants[id]
+		switch {
+		case old == nil:
+			added = append(added, p.Name)
+		case old.Name != p.Name:
+			renamed = append(renamed, old.Name+" → "+p.Name)
+		}
+		if old == nil {
+			continue
+		}
+		switch {
+		case p.Archived && !old.Archived:
+			archived = append(archived, p.Name)
+		case !p.Archived && old.Archived:
+			restored = append(restored, p.Name)
+		}
+		if p.Disabled != old.Disabled {
+			toggled = append(toggled, p.Name)
+		}
+	}
+	for id, p := range from.Participants {
+		if to.Participants[id] == nil {
+			removed = append(removed, p.Name)
+		}
+	}
+	ps = appendNames(ps, "added", added)
+	ps = appendNames(ps, "removed", removed)
+	ps = appendNames(ps, "renamed", renamed)
+	ps = appendNames(ps, "archived", archived)
+	ps = appendNames(ps, "restored", restored)
+	ps = appendNames(ps, "(de)activated", toggled)
+	add("participants", ps)
+
+	add("tunes", countChanges(keyed(from.Tunes, tuneKey), keyed(to.Tunes, tuneKey)))
+	add("sessions", countChanges(keyed(from.Sessions, func(s *Session) string { return s.ID }),
+		keyed(to.Sessions, func(s *Session) string { return s.ID })))
+	add("themes", countChanges(keyed(from.Themes, func(t *Theme) string { return t.ID }),
+		keyed(to.Themes, func(t *Theme) string { return t.ID })))
+	return out
+}
+
+// appendNames adds "verb a, b" to parts when there are names.
+func appendNames(parts []string, verb string, names []string) []string {
+	if len(names) == 0 {
+		return parts
+	}
+	sort.Strings(names)
+	return append(parts, verb+" "+strings.Join(names, ", "))
+}
+
+// tuneKey tells tunes apart; tunes have no ID of their own.
+func tuneKey(t Tune) string {
+	return fmt.Sprint(t.Link, "|", t.AddedAt.UnixNano())
+}
+
+// keyed maps the records by key, numbering records that share a key.
+func keyed[T any](records []T, key func(T) string) map[string]T {
+	out := make(map[string]T, len(records))
+	for _, r := range records {
+		k := key(r)
+		for n := 1; ; n++ {
+			if _, dup := out[k]; !dup {
+				break
+			}
+			k = fmt.Sprint(key(r), "#", n)
+		}
+		out[k] = r
+	}
+	return out
+}
+
+// countChanges counts the records added, removed and changed between two
+// keyed sets.
+func countChanges[T any](from, to map[string]T) []string {
+	var added, removed, changed int
+	for k, r := range to {
+		old, ok := from[k]
+		switch {
+		case !ok:
+			added++
+		case !reflect.DeepEqual(old, r):
+			changed++
+		}
+	}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			removed++
+		}
+	}
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		verb string
+	}{{added, "added"}, {removed, "removed"}, {changed, "changed"}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.verb))
+		}
+	}
+	return parts
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	from := NewData()
+	ann, _ := from.AddParticipant("Ann")
+	bob, _ := from.AddParticipant("Bob")
+	cid, _ := from.AddParticipant("Cid")
+	day := time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC)
+	from.Tunes = []Tune{
+		{Link: "a", ParticipantID: ann.ID, AddedAt: day},
+		{Link: "b", ParticipantID: bob.ID, AddedAt: day},
+	}
+	if got := Diff(from, from); len(got) != 0 {
+		t.Fatalf("Diff of the same data = %v", got)
+	}
+
+	to := NewData()
+	for id, p := range from.Participants {
+		c := *p
+		to.Participants[id] = &c
+	}
+	to.Tunes = append([]Tune(nil), from.Tunes...)
+	_ = to.RemoveParticipant(cid.ID)
+	_ = to.RenameParticipant(bob.ID, "Bobby")
+	_ = to.ArchiveParticipant(ann.ID, day)
+	dee, _ := to.AddParticipant("Dee")
+	to.Tunes[0].Tags = []string{"cover"}
+	to.Tunes = append(to.Tunes, Tune{Link: "c", ParticipantID: dee.ID, AddedAt: day})
+	to.SessionOn(day)
+
+	want := []string{
+		"participants: added Dee, removed Cid, renamed Bob → Bobby, archived Ann",
+		"tunes: 1 added, 1 changed",
+		"sessions: 1 added",
+	}
+	if got := Diff(from, to); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff =\n%q\nwant\n%q", got, want)
+	}
+}
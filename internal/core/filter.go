@@ -0,0 +1,148 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TuneSort is an order for the tune list.
+type TuneSort int
+
+const (
+	SortNewest TuneSort = iota
+	SortOldest
+	SortTitle
+	SortScore
+	SortProvider
+)
+
+// TuneSorts lists every order in menu order.
+var TuneSorts = []TuneSort{SortNewest, SortOldest, SortTitle, SortScore, SortProvider}
+
+func (s TuneSort) String() string {
+	switch s {
+	case SortOldest:
+		return "Oldest first"
+	case SortTitle:
+		return "Title"
+	case SortScore:
+		return "Score"
+	case SortProvider:
+		return "Provider"
+	}
+	return "Newest first"
+}
+
+// Unrated as TuneFilter.MinScore keeps only tunes without votes.
+const Unrated = -1
+
+// TuneFilter narrows the tune list. Zero fields match everything.
+type TuneFilter struct {
+	Query         string    // words that must all appear in title, link, channel, provider or tags
+	From, To      time.Time // days added, both inclusive
+	ParticipantID string
+	Platform      string
+	Theme         string
+	MinScore      float64 // lowest average score, or Unrated
+}
+
+// Match reports whether tune t passes the filter.
+func (d *Data) Match(f TuneFilter, t Tune) bool {
+	if f.ParticipantID != "" && t.ParticipantID != f.ParticipantID {
+		return false
+	}
+	if f.Platform != "" && t.Platform() != f.Platform {
+		return false
+	}
+	if f.Theme != "" && !strings.EqualFold(t.Theme, f.Theme) {
+		return false
+	}
+	if !f.From.IsZero() && (t.AddedAt.IsZero() || t.AddedAt.Before(dayOf(f.From))) {
+		return false
+	}
+	if !f.To.IsZero() && (t.AddedAt.IsZero() || !t.AddedAt.Before(dayOf(f.To).AddDate(0, 0, 1))) {
+		return false
+	}
+	switch {
+	case f.MinScore == Unrated:
+		if len(t.Votes) > 0 {
+			return false
+		}
+	case f.MinScore > 0:
+		if len(t.Votes) == 0 || t.Score() < f.MinScore {
+			return false
+		}
+	}
+	if q := strings.Fields(strings.ToLower(f.Query)); len(q) > 0 {
+		text := strings.ToLower(strings.Join(append([]string{
+			t.Name, t.Link, t.Channel, d.ParticipantName(t.ParticipantID),
+		}, t.Tags...), "\n"))
+		for _, word := range q {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FilterTunes returns the positions in d.Tunes of the tunes passing f, in
+// the given order.
+func (d *Data) FilterTunes(f TuneFilter, by TuneSort) []int {
+	var out []int
+	for i, t := range d.Tunes {
+		if d.Match(f, t) {
+			out = append(out, i)
+		}
+	}
+	less := func(a, b Tune) bool { return a.AddedAt.Before(b.AddedAt) }
+	switch by {
+	case SortNewest:
+		less = func(a, b Tune) bool { return a.AddedAt.After(b.AddedAt) }
+	case SortTitle:
+		less = func(a, b Tune) bool { return strings.ToLower(a.Title()) < strings.ToLower(b.Title()) }
+	case SortScore:
+		less = func(a, b Tune) bool {
+			if (len(a.Votes) > 0) != (len(b.Votes) > 0) {
+				return len(a.Votes) > 0
+			}
+			return a.Score() > b.Score()
+		}
+	case SortProvider:
+		less = func(a, b Tune) bool {
+			return strings.ToLower(d.ParticipantName(a.ParticipantID)) < strings.ToLower(d.ParticipantName(b.ParticipantID))
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return less(d.Tunes[out[i]], d.Tunes[out[j]]) })
+	return out
+}
+
+// Platforms returns the distinct platforms of all tunes, sorted.
+func (d *Data) Platforms() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range d.Tunes {
+		if p := t.Platform(); !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParseTags splits a comma separated list into trimmed, de-duplicated tags.
+func ParseTags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func filterData() *Data {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	day := func(m time.Month, dd int) time.Time { return time.Date(2026, m, dd, 10, 0, 0, 0, time.Local) }
+	d.Tunes = []Tune{
+		{Name: "Bohemian Rhapsody", ID: "a", Provider: "youtube", Channel: "Queen", ParticipantID: ann.ID, AddedAt: day(1, 6),
+			Votes: map[string]int{bob.ID: 5}},
+		{Name: "Hallelujah", Link: "https://artist.bandcamp.com/track/h", ParticipantID: bob.ID, AddedAt: day(2, 3),
+			Tags: []string{"cover"}, Votes: map[string]int{ann.ID: 2}},
+		{Name: "Around the World", ID: "c", Provider: "youtube", ParticipantID: bob.ID, AddedAt: day(3, 3)},
+	}
+	return d
+}
+
+func ids(d *Data, rows []int) []string {
+	var out []string
+	for _, i := range rows {
+		out = append(out, d.Tunes[i].Name)
+	}
+	return out
+}
+
+func TestFilterTunes(t *testing.T) {
+	d := filterData()
+	bob := d.ParticipantByName("bob")
+
+	cases := []struct {
+		name string
+		f    TuneFilter
+		want []string
+	}{
+		{"all", TuneFilter{}, []string{"Around the World", "Hallelujah", "Bohemian Rhapsody"}},
+		{"query title", TuneFilter{Query: "WORLD"}, []string{"Around the World"}},
+		{"query provider and tag", TuneFilter{Query: "bob cover"}, []string{"Hallelujah"}},
+		{"query channel", TuneFilter{Query: "queen"}, []string{"Bohemian Rhapsody"}},
+		{"participant", TuneFilter{ParticipantID: bob.ID}, []string{"Around the World", "Hallelujah"}},
+		{"platform", TuneFilter{Platform: "Bandcamp"}, []string{"Hallelujah"}},
+		{"date range", TuneFilter{From: time.Date(2026, 2, 3, 0, 0, 0, 0, time.Local), To: time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)},
+			[]string{"Hallelujah"}},
+		{"min score", TuneFilter{MinScore: 4}, []string{"Bohemian Rhapsody"}},
+		{"unrated", TuneFilter{MinScore: Unrated}, []string{"Around the World"}},
+	}
+	for _, c := range cases {
+		if got := ids(d, d.FilterTunes(c.f, SortNewest)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFilterTunesOrder(t *testing.T) {
+	d := filterData()
+	cases := map[TuneSort][]string{
+		SortOldest:   {"Bohemian Rhapsody", "Hallelujah", "Around the World"},
+		SortTitle:    {"Around the World", "Bohemian Rhapsody", "Hallelujah"},
+		SortScore:    {"Bohemian Rhapsody", "Hallelujah", "Around the World"},
+		SortProvider: {"Bohemian Rhapsody", "Hallelujah", "Around the World"},
+	}
+	for by, want := range cases {
+		if got := ids(d, d.FilterTunes(TuneFilter{}, by)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", by, got, want)
+		}
+	}
+}
+
+func TestSortTitleUsesLinkWithoutName(t *testing.T) {
+	d := filterData()
+	d.Tunes = append(d.Tunes, Tune{Link: "https://www.mixcloud.com/dj/set/"})
+	got := d.FilterTunes(TuneFilter{}, SortTitle)
+	if last := d.Tunes[got[len(got)-1]]; last.Title() != "mixcloud.com/dj/set/" {
+		t.Errorf("last by title is %q, want the unnamed mixcloud tune", last.Title())
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" cover, live ,, Cover,80s")
+	if want := []string{"cover", "live", "80s"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseTags = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// MaxHistory is the number of actions that can be undone.
+const MaxHistory = 20
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// History keeps copies of the data around undoable actions. It lasts for one
+// Tunesday: a history from an earlier day is forgotten.
+type History struct {
+	Day    string   `json:"day"`              // local date of the actions, 2006-01-02
+	Done   []Change `json:"done,omitempty"`   // undoable, oldest first
+	Undone []Change `json:"undone,omitempty"` // redoable, oldest first
+}
+
+// Change is an action in the history together with the data as it was
+// before it, when undoable, or after it, when redoable.
+type Change struct {
+	Action string          `json:"action"`
+	At     time.Time       `json:"at"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Expire forgets the history if it belongs to an earlier day than now.
+func (h *History) Expire(now time.Time) {
+	day := now.Format("2006-01-02")
+	if h.Day != day {
+		*h = History{Day: day}
+	}
+}
+
+// Record remembers d as it is before action and forgets what could be
+// redone. Only the last MaxHistory actions are kept.
+func (h *History) Record(action string, d *Data, now time.Time) error {
+	h.Expire(now)
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	h.Done = append(h.Done, Change{Action: action, At: now, Data: b})
+	if n := len(h.Done) - MaxHistory; n > 0 {
+		h.Done = append([]Change(nil), h.Done[n:]...)
+	}
+	h.Undone = nil
+	return nil
+}
+
+// LastUndo returns the action Undo would undo.
+func (h *History) LastUndo() (Change, bool) {
+	if len(h.Done) == 0 {
+		return Change{}, false
+	}
+	return h.Done[len(h.Done)-1], true
+}
+
+// LastRedo returns the action Redo would redo.
+func (h *History) LastRedo() (Change, bool) {
+	if len(h.Undone) == 0 {
+		return Change{}, false
+	}
+	return h.Undone[len(h.Undone)-1], true
+}
+
+// Undo puts d back to how it was before the last action and returns that
+// action. The action can then be redone.
+func (h *History) Undo(d *Data) (Change, error) {
+	if len(h.Done) == 0 {
+		return Change{}, ErrNothingToUndo
+	}
+	return restore(d, &h.Done, &h.Undone)
+}
+
+// Redo repeats the last undone action on d and returns it.
+func (h *History) Redo(d *Data) (Change, error) {
+	if len(h.Undone) == 0 {
+		return Change{}, ErrNothingToRedo
+	}
+	return restore(d, &h.Undone, &h.Done)
+}
+
+// restore replaces d with the last change of from and moves that change to
+// to, carrying the current data so it can be restored in turn.
+func restore(d *Data, from, to *[]Change) (Change, error) {
+	c := (*from)[len(*from)-1]
+	cur, err := json.Marshal(d)
+	if err != nil {
+		return Change{}, err
+	}
+	var prev Data
+	if err := json.Unmarshal(c.Data, &prev); err != nil {
+		return Change{}, err
+	}
+	if prev.Participants == nil {
+		prev.Participants = make(map[string]*Participant)
+	}
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, Change{Action: c.Action, At: c.At, Data: cur})
+	*d = prev
+	return c, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	now := time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC)
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	d.Tunes = []Tune{{ID: "a", ParticipantID: ann.ID}}
+
+	var h History
+	if err := h.Record("Remove Ann", d, now); err != nil {
+		t.Fatal(err)
+	}
+	_ = d.RemoveParticipant(ann.ID)
+
+	c, err := h.Undo(d)
+	if err != nil || c.Action != "Remove Ann" {
+		t.Fatalf("Undo = %+v, %v", c, err)
+	}
+	if d.Participant(ann.ID) == nil || len(d.Tunes) != 1 {
+		t.Fatalf("undo did not bring Ann and her tune back: %+v", d)
+	}
+	if _, err := h.Undo(d); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("second Undo: %v", err)
+	}
+
+	if _, err := h.Redo(d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Participant(ann.ID) != nil || len(d.Tunes) != 0 {
+		t.Fatalf("redo did not remove Ann again: %+v", d)
+	}
+	if _, err := h.Redo(d); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("second Redo: %v", err)
+	}
+
+	// a new action forgets what could be redone
+	_, _ = h.Undo(d)
+	_ = h.Record("Add Bob", d, now)
+	if _, ok := h.LastRedo(); ok {
+		t.Fatal("redo survived a new action")
+	}
+}
+
+func TestHistoryLimitsAndExpires(t *testing.T) {
+	now := time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC)
+	d := NewData()
+	var h History
+	for i := 0; i < MaxHistory+5; i++ {
+		_ = h.Record(fmt.Sprint("action ", i), d, now)
+	}
+	if len(h.Done) != MaxHistory || h.Done[0].Action != "action 5" {
+		t.Fatalf("kept %d actions starting with %q", len(h.Done), h.Done[0].Action)
+	}
+
+	h.Expire(now.Add(time.Hour))
+	if len(h.Done) != MaxHistory {
+		t.Fatal("history expired on the same day")
+	}
+	h.Expire(now.AddDate(0, 0, 7))
+	if _, ok := h.LastUndo(); ok {
+		t.Fatal("history of last week can still be undone")
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MergeReport counts what Merge took over.
+type MergeReport struct {
+	Participants int // participants added
+	Themes       int // themes added
+	Sessions     int // sessions added
+	Draws        int // draws added to sessions that were there already
+	Tunes        int // tunes added
+	Duplicates   int // tunes left out because they were there already
+}
+
+// Merge adds the records of from that d doesn't have yet. Participants and
+// themes are matched by name regardless of case, sessions by day and tunes by
+// video ID, or by link for tunes without one. Sessions of the same day get
+// the draws of participants they have none for. Draws, votes and tunes
+// follow the participants they belong to.
+func (d *Data) Merge(from *Data) MergeReport {
+	var r MergeReport
+	if d.Participants == nil {
+		d.Participants = make(map[string]*Participant)
+	}
+
+	people := make(map[string]string) // id in from -> id in d
+	for _, p := range from.SortedParticipants() {
+		if own := d.ParticipantByName(p.Name); own != nil {
+			people[p.ID] = own.ID
+			continue
+		}
+		cp := *p
+		if cp.ID == "" || d.Participants[cp.ID] != nil {
+			cp.ID = NewID()
+		}
+		d.Participants[cp.ID] = &cp
+		people[p.ID] = cp.ID
+		r.Participants++
+	}
+
+	themes := make(map[string]string)
+	for _, t := range from.Themes {
+		if own := d.ThemeByName(t.Name); own != nil {
+			themes[t.ID] = own.ID
+			continue
+		}
+		cp := *t
+		if cp.ID == "" || d.themeByID(cp.ID) != nil {
+			cp.ID = NewID()
+		}
+		d.Themes = append(d.Themes, &cp)
+		themes[t.ID] = cp.ID
+		r.Themes++
+	}
+
+	sessions := make(map[string]string)
+	for _, s := range from.Sessions {
+		own := d.LookupSession(s.Date)
+		isNew := own == nil
+		if isNew {
+			cp := *s
+			cp.ID = NewID()
+			cp.ThemeID = themes[s.ThemeID]
+			cp.Draws = nil
+			d.Sessions = append(d.Sessions, &cp)
+			own = &cp
+			r.Sessions++
+		}
+		sessions[s.ID] = own.ID
+
+		drawn := make(map[string]bool, len(own.Draws))
+		for _, dr := range own.Draws {
+			drawn[dr.ParticipantID] = true
+		}
+		for _, dr := range s.Draws {
+			id, ok := people[dr.ParticipantID]
+			if !ok || drawn[id] {
+				continue
+			}
+			drawn[id] = true
+			dc := *dr
+			dc.ParticipantID = id
+			own.Draws = append(own.Draws, &dc)
+			if !isNew {
+				r.Draws++
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(d.Tunes))
+	for _, t := range d.Tunes {
+		seen[tuneIdentity(t)] = true
+	}
+	for _, t := range from.Tunes {
+		k := tuneIdentity(t)
+		if seen[k] {
+			r.Duplicates++
+			continue
+		}
+		seen[k] = true
+		t.ParticipantID = people[t.ParticipantID]
+		t.SessionID = sessions[t.SessionID]
+		t.Tags = slices.Clone(t.Tags)
+		if len(t.Votes) > 0 {
+			votes := make(map[string]int, len(t.Votes))
+			for id, stars := range t.Votes {
+				if own, ok := people[id]; ok {
+					votes[own] = stars
+				}
+			}
+			t.Votes = votes
+		}
+		d.Tunes = append(d.Tunes, t)
+		r.Tunes++
+	}
+	return r
+}
+
+// Clone returns a deep copy of d.
+func (d *Data) Clone() *Data {
+	b, err := json.Marshal(d)
+	if err != nil {
+		panic(err) // Data has nothing that can't be encoded
+	}
+	var c Data
+	if err := json.Unmarshal(b, &c); err != nil {
+		panic(err)
+	}
+	return &c
+}
+
+// tuneIdentity tells whether two tunes are the same video.
+func tuneIdentity(t Tune) string {
+	if t.ID != "" {
+		return "id:" + t.ID
+	}
+	return "link:" + t.Link
+}
+
+func (d *Data) themeByID(id string) *Theme {
+	for _, t := range d.Themes {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
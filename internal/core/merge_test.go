@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeDedupesAndFollowsParticipants(t *testing.T) {
+	day := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	d.Tunes = []Tune{{ID: "one", Name: "One", ParticipantID: ann.ID}}
+	d.Sessions = []*Session{{ID: "s1", Date: day, Draws: []*Draw{{ParticipantID: ann.ID, Outcome: OutcomeDelivered}}}}
+
+	from := NewData()
+	from.Participants["x"] = &Participant{ID: "x", Name: "ANN"}
+	from.Participants["y"] = &Participant{ID: "y", Name: "Bob"}
+	from.Themes = []*Theme{{ID: "t", Name: "Covers"}}
+	from.Sessions = []*Session{
+		{ID: "s2", Date: day, Draws: []*Draw{{ParticipantID: "x", Outcome: OutcomePassed}, {ParticipantID: "y", Outcome: OutcomeDelivered}}},
+		{ID: "s3", Date: day.AddDate(0, 0, 7), ThemeID: "t", Draws: []*Draw{{ParticipantID: "y"}, {ParticipantID: "gone"}}},
+	}
+	from.Tunes = []Tune{
+		{ID: "one", Name: "One again", ParticipantID: "y"},
+		{ID: "two", Name: "Two", ParticipantID: "y", SessionID: "s3", Votes: map[string]int{"x": 4, "gone": 1}},
+		{Link: "https://example.com/three", Name: "Three", ParticipantID: "x"},
+		{Link: "https://example.com/three", Name: "Three twice"},
+	}
+
+	r := d.Merge(from)
+	if want := (MergeReport{Participants: 1, Themes: 1, Sessions: 1, Draws: 1, Tunes: 2, Duplicates: 2}); r != want {
+		t.Fatalf("report = %+v, want %+v", r, want)
+	}
+	bob := d.ParticipantByName("Bob")
+	if bob == nil || bob.ID != "y" {
+		t.Fatalf("Bob = %+v", bob)
+	}
+	two := d.Tunes[1]
+	if two.ParticipantID != bob.ID || len(two.Votes) != 1 || two.Votes[ann.ID] != 4 {
+		t.Errorf("tune two = %+v", two)
+	}
+	if d.Tunes[2].ParticipantID != ann.ID {
+		t.Errorf("tune three brought by %q, want Ann", d.Tunes[2].ParticipantID)
+	}
+	s := d.LookupSession(day.AddDate(0, 0, 7))
+	if s == nil || two.SessionID != s.ID || s.ThemeID != d.Themes[0].ID || len(s.Draws) != 1 || s.Draws[0].ParticipantID != bob.ID {
+		t.Errorf("session = %+v", s)
+	}
+	// the day both have keeps Ann's own draw and gains Bob's
+	if s := d.LookupSession(day); len(s.Draws) != 2 || s.Draws[0].Outcome != OutcomeDelivered || s.Draws[1].ParticipantID != bob.ID {
+		t.Errorf("session of the day both have = %+v", s.Draws)
+	}
+	if from.Tunes[1].ParticipantID != "y" || from.Sessions[1].Draws[0].ParticipantID != "y" {
+		t.Error("Merge changed the data merged from")
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNewerVersion is returned for data written by a newer tunesday, which
+// may hold fields this build doesn't know and would drop on the next save.
+var ErrNewerVersion = errors.New("the data was written by a newer tunesday, update to open it")
+
+// UnmarshalJSON decodes Data and upgrades files written by older versions.
+func (d *Data) UnmarshalJSON(b []byte) error {
+	type plain Data
+	var raw struct {
+		plain
+		Participants json.RawMessage `json:"participants"`
+		Disabled     map[string]bool `json:"disabled,omitempty"` // legacy, version 1
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if raw.Version > CurrentVersion {
+		return fmt.Errorf("%w (version %d, this build reads up to %d)", ErrNewerVersion, raw.Version, CurrentVersion)
+	}
+	*d = Data(raw.plain)
+
+	if isLegacyParticipants(raw.Participants) {
+		// version 1 stored name -> tunes count plus a parallel disabled map
+		var counts map[string]int
+		if err := json.Unmarshal(raw.Participants, &counts); err != nil {
+			return participantsError(b, raw.Participants, err)
+		}
+		migrateV1(d, counts, raw.Disabled)
+	} else if len(raw.Participants) > 0 {
+		if err := json.Unmarshal(raw.Participants, &d.Participants); err != nil {
+			return participantsError(b, raw.Participants, err)
+		}
+	}
+	d.Version = CurrentVersion
+
+	if d.Participants == nil {
+		d.Participants = make(map[string]*Participant)
+	}
+	for id, p := range d.Participants {
+		if p == nil {
+			delete(d.Participants, id)
+			continue
+		}
+		if p.ID == "" {
+			p.ID = id
+		}
+	}
+	return nil
+}
+
+// participantsError makes a type error in the participants object point into
+// the whole document, so that its offset and field are those of the file.
+func participantsError(doc, participants []byte, err error) error {
+	var te *json.UnmarshalTypeError
+	if !errors.As(err, &te) {
+		return err
+	}
+	out := *te
+	out.Field = strings.TrimSuffix("participants."+te.Field, ".")
+	if at := bytes.Index(doc, participants); at >= 0 {
+		out.Offset += int64(at)
+	}
+	return &out
+}
+
+// migrateV1 turns the name-keyed maps of version 1 into participant records.
+// Tunes whose provider matches a participant name are attributed to them.
+func migrateV1(d *Data, counts map[string]int, disabled map[string]bool) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d.Participants = make(map[string]*Participant, len(names))
+	byName := make(map[string]string, len(names))
+	for _, name := range names {
+		p := &Participant{ID: NewID(), Name: name, LegacyDraws: counts[name], Disabled: disabled[name]}
+		d.Participants[p.ID] = p
+		byName[name] = p.ID
+	}
+	for i := range d.Tunes {
+		if id, ok := byName[d.Tunes[i].Provider]; ok && d.Tunes[i].ParticipantID == "" {
+			d.Tunes[i].ParticipantID = id
+		}
+	}
+}
+
+// isLegacyParticipants reports whether the participants object maps names to
+// plain numbers, as version 1 did.
+func isLegacyParticipants(b json.RawMessage) bool {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return false
+	}
+	for _, v := range m {
+		v = bytes.TrimSpace(v)
+		if len(v) > 0 && (v[0] == '-' || (v[0] >= '0' && v[0] <= '9')) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptyName           = errors.New("participant name is empty")
+	ErrParticipantExists   = errors.New("participant already exists")
+	ErrParticipantNotFound = errors.New("participant not found")
+)
+
+// Participant is a Tunesday team member. The ID never changes, so the
+// display name can be edited without losing history.
+type Participant struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`             // display name
+	Handle      string            `json:"handle,omitempty"` // short nickname, e.g. chat handle
+	ChatUserID  string            `json:"chat_user_id,omitempty"`
+	Email       string            `json:"email,omitempty"`
+	JoinedAt    time.Time         `json:"joined_at,omitempty"`
+	Disabled    bool              `json:"disabled,omitempty"`
+	Archived    bool              `json:"archived,omitempty"`    // left the team; kept for history and stats
+	ArchivedAt  time.Time         `json:"archived_at,omitempty"` // when they were archived
+	LegacyDraws int               `json:"count,omitempty"`       // draws made before history was kept
+	Preferences map[string]string `json:"preferences,omitempty"`
+}
+
+// NewID returns a random identifier suitable for participants and other records.
+func NewID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// AddParticipant creates a participant with the given display name.
+// Names are unique regardless of case.
+func (d *Data) AddParticipant(name string) (*Participant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	if d.ParticipantByName(name) != nil {
+		return nil, ErrParticipantExists
+	}
+	if d.Participants == nil {
+		d.Participants = make(map[string]*Participant)
+	}
+	p := &Participant{ID: NewID(), Name: name, JoinedAt: time.Now()}
+	d.Participants[p.ID] = p
+	return p, nil
+}
+
+// Participant returns the participant with the given ID or nil.
+func (d *Data) Participant(id string) *Participant {
+	return d.Participants[id]
+}
+
+// ParticipantByName looks a participant up by display name or handle, ignoring case.
+func (d *Data) ParticipantByName(name string) *Participant {
+	name = strings.TrimSpace(name)
+	for _, p := range d.Participants {
+		if strings.EqualFold(p.Name, name) || (p.Handle != "" && strings.EqualFold(p.Handle, name)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// RenameParticipant changes the display name of a participant. Tunes refer to
+// participants by ID, so their history stays attached.
+func (d *Data) RenameParticipant(id, name string) error {
+	p := d.Participant(id)
+	if p == nil {
+		return ErrParticipantNotFound
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrEmptyName
+	}
+	if other := d.ParticipantByName(name); other != nil && other.ID != id {
+		return ErrParticipantExists
+	}
+	p.Name = name
+	return nil
+}
+
+// RemoveParticipant deletes a participant together with the tunes they provided.
+func (d *Data) RemoveParticipant(id string) error {
+	if d.Participant(id) == nil {
+		return ErrParticipantNotFound
+	}
+	delete(d.Participants, id)
+	tunes := d.Tunes[:0]
+	for _, t := range d.Tunes {
+		if t.ParticipantID != id {
+			tunes = append(tunes, t)
+		}
+	}
+	d.Tunes = tunes
+	return nil
+}
+
+// ArchiveParticipant marks a participant who left the team. Unlike removing
+// them it keeps their tunes, votes and draws.
+func (d *Data) ArchiveParticipant(id string, now time.Time) error {
+	p := d.Participant(id)
+	if p == nil {
+		return ErrParticipantNotFound
+	}
+	p.Archived, p.ArchivedAt = true, now
+	return nil
+}
+
+// RestoreParticipant brings an archived participant back to the team.
+func (d *Data) RestoreParticipant(id string) error {
+	p := d.Participant(id)
+	if p == nil {
+		return ErrParticipantNotFound
+	}
+	p.Archived, p.ArchivedAt = false, time.Time{}
+	return nil
+}
+
+// MemberOn reports whether p belonged to the team on day: joined by then and
+// not archived before it.
+func (p *Participant) MemberOn(day time.Time) bool {
+	if !p.JoinedAt.IsZero() && p.JoinedAt.After(day.AddDate(0, 0, 1)) {
+		return false
+	}
+	return !p.Archived || p.ArchivedAt.IsZero() || p.ArchivedAt.After(day)
+}
+
+// SortedParticipants returns all participants, archived ones included,
+// ordered by display name.
+func (d *Data) SortedParticipants() []*Participant {
+	ps := make([]*Participant, 0, len(d.Participants))
+	for _, p := range d.Participants {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		a, b := strings.ToLower(ps[i].Name), strings.ToLower(ps[j].Name)
+		if a != b {
+			return a < b
+		}
+		return ps[i].ID < ps[j].ID
+	})
+	return ps
+}
+
+// CurrentParticipants returns the participants who haven't been archived,
+// ordered by name.
+func (d *Data) CurrentParticipants() []*Participant {
+	var ps []*Participant
+	for _, p := range d.SortedParticipants() {
+		if !p.Archived {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// ArchivedParticipants returns the participants who left the team, ordered
+// by name.
+func (d *Data) ArchivedParticipants() []*Participant {
+	var ps []*Participant
+	for _, p := range d.SortedParticipants() {
+		if p.Archived {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// ActiveParticipants returns the participants eligible for a draw, ordered by name.
+func (d *Data) ActiveParticipants() []*Participant {
+	var ps []*Participant
+	for _, p := range d.CurrentParticipants() {
+		if !p.Disabled {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// ParticipantName returns the display name for id, or "" when unknown.
+func (d *Data) ParticipantName(id string) string {
+	if p := d.Participant(id); p != nil {
+		return p.Name
+	}
+	return ""
+}
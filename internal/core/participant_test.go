@@ -0,0 +1,193 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddParticipantRejectsDuplicatesIgnoringCase(t *testing.T) {
+	d := NewData()
+	p, err := d.AddParticipant("Ann")
+	if err != nil {
+		t.Fatalf("AddParticipant: %v", err)
+	}
+	if p.ID == "" || d.Participant(p.ID) != p {
+		t.Fatalf("participant not stored under its ID: %+v", p)
+	}
+	if _, err := d.AddParticipant("  ann "); !errors.Is(err, ErrParticipantExists) {
+		t.Fatalf("expected ErrParticipantExists, got %v", err)
+	}
+	if _, err := d.AddParticipant(" "); !errors.Is(err, ErrEmptyName) {
+		t.Fatalf("expected ErrEmptyName, got %v", err)
+	}
+}
+
+func TestRenameParticipantKeepsTunes(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	d.Tunes = append(d.Tunes, Tune{ID: "x", ParticipantID: ann.ID})
+
+	if err := d.RenameParticipant(ann.ID, "Bob"); !errors.Is(err, ErrParticipantExists) {
+		t.Fatalf("expected ErrParticipantExists, got %v", err)
+	}
+	if err := d.RenameParticipant(ann.ID, "Annie"); err != nil {
+		t.Fatalf("RenameParticipant: %v", err)
+	}
+	if got := d.ParticipantName(d.Tunes[0].ParticipantID); got != "Annie" {
+		t.Fatalf("tune provider = %q, want Annie", got)
+	}
+	if err := d.RenameParticipant(bob.ID, "bob"); err != nil {
+		t.Fatalf("changing only the case of your own name should work: %v", err)
+	}
+}
+
+func TestRemoveParticipantDropsTheirTunes(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	d.Tunes = []Tune{{ID: "a", ParticipantID: ann.ID}, {ID: "b", ParticipantID: bob.ID}}
+	if err := d.RemoveParticipant(ann.ID); err != nil {
+		t.Fatalf("RemoveParticipant: %v", err)
+	}
+	if len(d.Tunes) != 1 || d.Tunes[0].ID != "b" {
+		t.Fatalf("unexpected tunes after remove: %+v", d.Tunes)
+	}
+	if err := d.RemoveParticipant(ann.ID); !errors.Is(err, ErrParticipantNotFound) {
+		t.Fatalf("expected ErrParticipantNotFound, got %v", err)
+	}
+}
+
+func TestUnmarshalMigratesVersion1(t *testing.T) {
+	legacy := `{
+		"participants": {"Ann": 2, "Bob": 0},
+		"disabled": {"Bob": true},
+		"tunes": [{"name": "Song", "id": "x", "provider": "Ann"}, {"name": "Other", "id": "y", "provider": "youtube"}]
+	}`
+	var d Data
+	if err := json.Unmarshal([]byte(legacy), &d); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if d.Version != CurrentVersion || len(d.Participants) != 2 {
+		t.Fatalf("unexpected data after migration: %+v", d)
+	}
+	ann := d.ParticipantByName("Ann")
+	if ann == nil || ann.LegacyDraws != 2 || ann.Disabled {
+		t.Fatalf("unexpected Ann: %+v", ann)
+	}
+	if bob := d.ParticipantByName("Bob"); bob == nil || !bob.Disabled {
+		t.Fatalf("unexpected Bob: %+v", bob)
+	}
+	if d.Tunes[0].ParticipantID != ann.ID {
+		t.Fatalf("tune not attributed to Ann: %+v", d.Tunes[0])
+	}
+	if d.Tunes[1].ParticipantID != "" {
+		t.Fatalf("tune unexpectedly attributed: %+v", d.Tunes[1])
+	}
+
+	// a second round trip must not migrate again
+	b, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var again Data
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if p := again.Participant(ann.ID); p == nil || p.Name != "Ann" || p.LegacyDraws != 2 {
+		t.Fatalf("participant lost in round trip: %+v", again.Participants)
+	}
+}
+
+func TestArchiveParticipantKeepsHistory(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	d.Tunes = []Tune{{ID: "a", ParticipantID: ann.ID}}
+	left := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	ann.JoinedAt = left.AddDate(-1, 0, 0)
+
+	if err := d.ArchiveParticipant(ann.ID, left); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tunes) != 1 || d.Counts()[ann.ID].Delivered != 1 {
+		t.Fatal("archiving dropped Ann's tunes")
+	}
+	if got := d.ActiveParticipants(); len(got) != 1 || got[0] != bob {
+		t.Fatalf("active = %v, want only Bob", got)
+	}
+	if got := d.ArchivedParticipants(); len(got) != 1 || got[0] != ann {
+		t.Fatalf("archived = %v", got)
+	}
+	if !ann.MemberOn(left.AddDate(0, -1, 0)) || ann.MemberOn(left.AddDate(0, 1, 0)) {
+		t.Fatal("Ann should be a member before she left and not after")
+	}
+
+	if err := d.RestoreParticipant(ann.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.CurrentParticipants()) != 2 || !ann.MemberOn(left.AddDate(0, 1, 0)) {
+		t.Fatal("Ann was not restored")
+	}
+	if err := d.ArchiveParticipant("nobody", left); !errors.Is(err, ErrParticipantNotFound) {
+		t.Fatalf("archiving an unknown participant: %v", err)
+	}
+}
+
+func TestUnmarshalRejectsNewerVersion(t *testing.T) {
+	var d Data
+	newer := fmt.Sprintf(`{"version": %d, "participants": {}, "moods": ["sunny"]}`, CurrentVersion+1)
+	if err := json.Unmarshal([]byte(newer), &d); !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("unmarshal = %v, want ErrNewerVersion", err)
+	}
+}
+
+// Every format version has a file in testdata. A version that adds fields
+// gets a file using them, so that a round trip shows none is dropped.
+func TestUnmarshalEveryVersion(t *testing.T) {
+	for v := 1; v <= CurrentVersion; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("v%d.json", v)))
+			if err != nil {
+				t.Fatalf("no file for version %d: %v", v, err)
+			}
+			var d Data
+			if err := json.Unmarshal(b, &d); err != nil {
+				t.Fatal(err)
+			}
+			ann := d.ParticipantByName("Ann")
+			if d.Version != CurrentVersion || ann == nil || len(d.Tunes) != 1 || d.Tunes[0].ParticipantID != ann.ID {
+				t.Fatalf("data = %+v", d)
+			}
+			if drawn := d.Counts()[ann.ID].Drawn; drawn < 2 {
+				t.Errorf("Ann was drawn %d times, want the 2 from before at least", drawn)
+			}
+
+			out, err := json.Marshal(&d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again Data
+			if err := json.Unmarshal(out, &again); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&again, &d) {
+				t.Errorf("round trip changed the data:\n%+v\nwant\n%+v", again, d)
+			}
+			if v == CurrentVersion {
+				var before, after map[string]any
+				json.Unmarshal(b, &before)
+				json.Unmarshal(out, &after)
+				if !reflect.DeepEqual(before, after) {
+					t.Errorf("saving a version %d file changed it:\n%s", v, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// Outcome describes what became of a draw.
+type Outcome string
+
+const (
+	OutcomePending   Outcome = "pending"   // drawn, no tune yet
+	OutcomeDelivered Outcome = "delivered" // a tune was added for the draw
+	OutcomeSkipped   Outcome = "skipped"   // drawn, but no tune came of it
+	OutcomeSwapped   Outcome = "swapped"   // handed over to a volunteer
+	OutcomePassed    Outcome = "passed"    // passed this week and owes a tune
+)
+
+// Session is a single Tunesday and the draws made on it.
+type Session struct {
+	ID      string    `json:"id"`
+	Date    time.Time `json:"date"`               // local midnight of the day
+	Theme   string    `json:"theme,omitempty"`    // optional theme of the round
+	ThemeID string    `json:"theme_id,omitempty"` // catalogue theme, when drawn from it
+	Slots   int       `json:"slots,omitempty"`    // providers to draw, 0 means one
+	Draws   []*Draw   `json:"draws,omitempty"`
+}
+
+// Draw records one participant being picked as tune provider.
+type Draw struct {
+	ParticipantID string    `json:"participant_id"`
+	At            time.Time `json:"at"`
+	Outcome       Outcome   `json:"outcome"`
+	Volunteer     bool      `json:"volunteer,omitempty"` // stepped in instead of being drawn
+}
+
+// Counts summarizes a participant's history.
+type Counts struct {
+	Drawn       int `json:"drawn"`       // times picked by a draw
+	Delivered   int `json:"delivered"`   // tunes in the list attributed to them
+	Skipped     int `json:"skipped"`     // draws that ended without a tune
+	Swapped     int `json:"swapped"`     // draws handed over to a volunteer
+	Passed      int `json:"passed"`      // draws passed on with a promise to deliver later
+	Volunteered int `json:"volunteered"` // times they stepped in for someone else
+	Owes        int `json:"owes"`        // passes not yet made good by a delivery
+}
+
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// SessionOn returns the session for the day of t, creating it when needed.
+func (d *Data) SessionOn(t time.Time) *Session {
+	if s := d.LookupSession(t); s != nil {
+		return s
+	}
+	s := &Session{ID: NewID(), Date: dayOf(t)}
+	d.Sessions = append(d.Sessions, s)
+	return s
+}
+
+// LookupSession returns the session for the day of t, or nil.
+func (d *Data) LookupSession(t time.Time) *Session {
+	day := dayOf(t)
+	for _, s := range d.Sessions {
+		if dayOf(s.Date.In(t.Location())).Equal(day) {
+			return s
+		}
+	}
+	return nil
+}
+
+// ProviderSlots returns how many providers the session wants, at least one.
+func (s *Session) ProviderSlots() int {
+	if s.Slots < 1 {
+		return 1
+	}
+	return s.Slots
+}
+
+// AddDraw records a pending draw for the participant.
+func (s *Session) AddDraw(participantID string, at time.Time) *Draw {
+	dr := &Draw{ParticipantID: participantID, At: at, Outcome: OutcomePending}
+	s.Draws = append(s.Draws, dr)
+	return dr
+}
+
+// Swap hands dr over to a volunteer and returns the volunteer's pending draw.
+func (s *Session) Swap(dr *Draw, volunteerID string, at time.Time) *Draw {
+	dr.Outcome = OutcomeSwapped
+	v := s.AddDraw(volunteerID, at)
+	v.Volunteer = true
+	return v
+}
+
+// PickProviders picks n distinct winners among candidates, or all of them
+// when there are fewer. Candidates who owe a tune are picked before everyone
+// else; shuffle, such as rand.Shuffle, decides within both groups. It returns
+// the winners' indices in candidates, in ascending order.
+func PickProviders(candidates []*Participant, n int, counts map[string]Counts, shuffle func(n int, swap func(i, j int))) []int {
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	var owing, rest []int
+	for i, p := range candidates {
+		if counts[p.ID].Owes > 0 {
+			owing = append(owing, i)
+		} else {
+			rest = append(rest, i)
+		}
+	}
+	shuffle(len(owing), func(i, j int) { owing[i], owing[j] = owing[j], owing[i] })
+	shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+	winners := append(owing, rest...)[:n]
+	sort.Ints(winners)
+	return winners
+}
+
+// SessionOf returns the session that holds dr, or nil.
+func (d *Data) SessionOf(dr *Draw) *Session {
+	for _, s := range d.Sessions {
+		for _, x := range s.Draws {
+			if x == dr {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// DeliverTune adds t to the tune list as the result of dr. The tune takes the
+// session's theme unless it already has one.
+func (d *Data) DeliverTune(dr *Draw, t Tune) {
+	t.ParticipantID = dr.ParticipantID
+	if s := d.SessionOf(dr); s != nil {
+		t.SessionID = s.ID
+		if t.Theme == "" {
+			t.Theme = s.Theme
+		}
+	}
+	dr.Outcome = OutcomeDelivered
+	d.Tunes = append(d.Tunes, t)
+}
+
+// Counts derives per-participant counts from sessions and tunes.
+// Draws made before history was kept are taken from Participant.LegacyDraws.
+// A pass is owed until the participant's next delivered draw.
+func (d *Data) Counts() map[string]Counts {
+	out := make(map[string]Counts, len(d.Participants))
+	for id, p := range d.Participants {
+		out[id] = Counts{Drawn: p.LegacyDraws}
+	}
+	sessions := append([]*Session(nil), d.Sessions...)
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Date.Before(sessions[j].Date) })
+	for _, s := range sessions {
+		for _, dr := range s.Draws {
+			c := out[dr.ParticipantID]
+			if dr.Volunteer {
+				c.Volunteered++
+			} else {
+				c.Drawn++
+			}
+			switch dr.Outcome {
+			case OutcomeSkipped:
+				c.Skipped++
+			case OutcomeSwapped:
+				c.Swapped++
+			case OutcomePassed:
+				c.Passed++
+				c.Owes++
+			case OutcomeDelivered:
+				if c.Owes > 0 {
+					c.Owes--
+				}
+			}
+			out[dr.ParticipantID] = c
+		}
+	}
+	for _, t := range d.Tunes {
+		if t.ParticipantID == "" {
+			continue
+		}
+		c := out[t.ParticipantID]
+		c.Delivered++
+		out[t.ParticipantID] = c
+	}
+	return out
+}
+
+// RecountReport lists what Recount changed.
+type RecountReport struct {
+	ClosedDraws       int // pending draws of past days marked skipped
+	AttributedTunes   int // tunes linked to a participant by provider name
+	SynthesizedDraws  int // delivered draws created for tunes without a session
+	LegacyDrawsMerged int // legacy draw counts absorbed into history
+}
+
+// Recount repairs history so that derived counts are consistent:
+// pending draws from earlier days become skipped, tunes are attributed by
+// provider name where possible, and tunes without a session get a delivered
+// draw on the day they were added. Those synthesized draws are taken out of
+// the participant's legacy draw count so they are not counted twice.
+func (d *Data) Recount(now time.Time) RecountReport {
+	var r RecountReport
+	today := dayOf(now)
+
+	for _, s := range d.Sessions {
+		if !dayOf(s.Date.In(now.Location())).Before(today) {
+			continue
+		}
+		for _, dr := range s.Draws {
+			if dr.Outcome == OutcomePending {
+				dr.Outcome = OutcomeSkipped
+				r.ClosedDraws++
+			}
+		}
+	}
+
+	for i := range d.Tunes {
+		t := &d.Tunes[i]
+		if t.ParticipantID == "" {
+			if p := d.ParticipantByName(t.Provider); p != nil {
+				t.ParticipantID = p.ID
+				r.AttributedTunes++
+			}
+		}
+		if t.ParticipantID == "" || t.SessionID != "" || t.AddedAt.IsZero() {
+			continue
+		}
+		p := d.Participant(t.ParticipantID)
+		if p == nil {
+			continue
+		}
+		s := d.SessionOn(t.AddedAt.In(now.Location()))
+		s.Draws = append(s.Draws, &Draw{ParticipantID: p.ID, At: t.AddedAt, Outcome: OutcomeDelivered})
+		t.SessionID = s.ID
+		r.SynthesizedDraws++
+		if p.LegacyDraws > 0 {
+			p.LegacyDraws--
+			r.LegacyDrawsMerged++
+		}
+	}
+	sort.SliceStable(d.Sessions, func(i, j int) bool { return d.Sessions[i].Date.Before(d.Sessions[j].Date) })
+	return r
+}
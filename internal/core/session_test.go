@@ -0,0 +1,116 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountsDistinguishDrawnDeliveredSkipped(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	now := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+
+	s := d.SessionOn(now)
+	skipped := s.AddDraw(ann.ID, now)
+	skipped.Outcome = OutcomeSkipped
+	d.DeliverTune(s.AddDraw(bob.ID, now), Tune{ID: "x", AddedAt: now})
+	s.AddDraw(ann.ID, now) // still pending
+
+	if d.SessionOn(now.Add(3*time.Hour)) != s {
+		t.Fatalf("expected the same session for the same day")
+	}
+
+	c := d.Counts()
+	if got := c[ann.ID]; got != (Counts{Drawn: 2, Skipped: 1}) {
+		t.Fatalf("Ann counts = %+v", got)
+	}
+	if got := c[bob.ID]; got != (Counts{Drawn: 1, Delivered: 1}) {
+		t.Fatalf("Bob counts = %+v", got)
+	}
+	if d.Tunes[0].ParticipantID != bob.ID || d.Tunes[0].SessionID != s.ID {
+		t.Fatalf("tune not linked to draw: %+v", d.Tunes[0])
+	}
+}
+
+func TestRecountRepairsLegacyHistory(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	ann.LegacyDraws = 3
+	added := time.Date(2026, 9, 1, 9, 0, 0, 0, time.Local)
+	d.Tunes = []Tune{
+		{ID: "a", Provider: "Ann", AddedAt: added},
+		{ID: "b", Provider: "youtube", AddedAt: added},
+	}
+	old := d.SessionOn(added.AddDate(0, 0, 7))
+	old.AddDraw(ann.ID, added.AddDate(0, 0, 7))
+
+	now := added.AddDate(0, 1, 0)
+	before := d.Counts()[ann.ID]
+	r := d.Recount(now)
+	if r.ClosedDraws != 1 || r.AttributedTunes != 1 || r.SynthesizedDraws != 1 || r.LegacyDrawsMerged != 1 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	after := d.Counts()[ann.ID]
+	if after.Drawn != before.Drawn || after.Delivered != 1 || after.Skipped != 1 {
+		t.Fatalf("counts before %+v after %+v", before, after)
+	}
+	if !d.Sessions[0].Date.Before(d.Sessions[1].Date) {
+		t.Fatalf("sessions not sorted by date")
+	}
+	if again := d.Recount(now); again != (RecountReport{}) {
+		t.Fatalf("second recount changed data: %+v", again)
+	}
+}
+
+func TestSwapAndPassCountTowardFairness(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	week1 := time.Date(2026, 10, 6, 10, 0, 0, 0, time.Local)
+	week2 := week1.AddDate(0, 0, 7)
+
+	s1 := d.SessionOn(week1)
+	s1.AddDraw(ann.ID, week1).Outcome = OutcomePassed
+	v := s1.Swap(s1.AddDraw(bob.ID, week1), ann.ID, week1)
+	d.DeliverTune(v, Tune{ID: "a1", AddedAt: week1})
+
+	c := d.Counts()
+	if got := c[ann.ID]; got != (Counts{Drawn: 1, Delivered: 1, Passed: 1, Volunteered: 1}) {
+		t.Fatalf("Ann counts after week 1 = %+v", got)
+	}
+	if got := c[bob.ID]; got != (Counts{Drawn: 1, Swapped: 1}) {
+		t.Fatalf("Bob counts after week 1 = %+v", got)
+	}
+
+	// the pass came before the volunteered tune, so that tune settles it
+	s2 := d.SessionOn(week2)
+	s2.AddDraw(ann.ID, week2).Outcome = OutcomePassed
+	if got := d.Counts()[ann.ID].Owes; got != 1 {
+		t.Fatalf("Ann owes %d after a second pass, want 1", got)
+	}
+	d.DeliverTune(s2.AddDraw(ann.ID, week2), Tune{ID: "a2", AddedAt: week2})
+	if got := d.Counts()[ann.ID].Owes; got != 0 {
+		t.Fatalf("Ann still owes %d after delivering", got)
+	}
+}
+
+func TestPickProvidersPrefersOwing(t *testing.T) {
+	var ps []*Participant
+	for _, id := range []string{"a", "b", "c", "d"} {
+		ps = append(ps, &Participant{ID: id})
+	}
+	counts := map[string]Counts{"c": {Owes: 1}}
+	reverse := func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+	got := PickProviders(ps, 2, counts, reverse)
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("picked %v, want c (owes) and d (first after the shuffle)", got)
+	}
+	if got := PickProviders(ps, 9, counts, reverse); len(got) != 4 {
+		t.Errorf("picked %v, want everyone", got)
+	}
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultThemeCooldownWeeks is used for new themes when no cooldown is given.
+const DefaultThemeCooldownWeeks = 8
+
+var (
+	ErrThemeExists   = errors.New("theme already exists")
+	ErrThemeNotFound = errors.New("theme not found")
+)
+
+// Theme is an entry of the team's theme catalogue.
+type Theme struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	CooldownWeeks int       `json:"cooldown_weeks"` // weeks before the theme may be drawn again
+	AddedAt       time.Time `json:"added_at,omitempty"`
+}
+
+// AddTheme adds a theme to the catalogue. Names are unique regardless of case.
+func (d *Data) AddTheme(name string, cooldownWeeks int) (*Theme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	if d.ThemeByName(name) != nil {
+		return nil, ErrThemeExists
+	}
+	if cooldownWeeks < 0 {
+		cooldownWeeks = 0
+	}
+	t := &Theme{ID: NewID(), Name: name, CooldownWeeks: cooldownWeeks, AddedAt: time.Now()}
+	d.Themes = append(d.Themes, t)
+	return t, nil
+}
+
+// ThemeByName returns the catalogue theme with the given name, ignoring case.
+func (d *Data) ThemeByName(name string) *Theme {
+	name = strings.TrimSpace(name)
+	for _, t := range d.Themes {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// RemoveTheme deletes a theme from the catalogue. Sessions and tunes keep
+// the theme name they were played with.
+func (d *Data) RemoveTheme(id string) error {
+	for i, t := range d.Themes {
+		if t.ID == id {
+			d.Themes = append(d.Themes[:i], d.Themes[i+1:]...)
+			return nil
+		}
+	}
+	return ErrThemeNotFound
+}
+
+// SortedThemes returns the catalogue ordered by name.
+func (d *Data) SortedThemes() []*Theme {
+	ts := append([]*Theme(nil), d.Themes...)
+	sort.Slice(ts, func(i, j int) bool { return strings.ToLower(ts[i].Name) < strings.ToLower(ts[j].Name) })
+	return ts
+}
+
+// ThemeLastUsed returns the day of the latest session that played the theme,
+// or the zero time when it was never used.
+func (d *Data) ThemeLastUsed(t *Theme) time.Time {
+	var last time.Time
+	for _, s := range d.Sessions {
+		if (s.ThemeID == t.ID || strings.EqualFold(s.Theme, t.Name)) && s.Date.After(last) {
+			last = s.Date
+		}
+	}
+	return last
+}
+
+// ThemeAvailableFrom returns the first day the theme may be drawn again, as
+// seen on the day of now, or the zero time when it has no cooldown to sit
+// out. The session of that day does not count as a use, so a theme can be
+// redrawn on the same day.
+func (d *Data) ThemeAvailableFrom(t *Theme, now time.Time) time.Time {
+	today := dayOf(now)
+	var from time.Time
+	for _, s := range d.Sessions {
+		day := dayOf(s.Date.In(today.Location()))
+		if !day.Before(today) || !(s.ThemeID == t.ID || strings.EqualFold(s.Theme, t.Name)) {
+			continue
+		}
+		if free := day.AddDate(0, 0, 7*t.CooldownWeeks); free.After(from) {
+			from = free
+		}
+	}
+	return from
+}
+
+// AvailableThemes returns the themes out of cooldown at now, ordered by name.
+func (d *Data) AvailableThemes(now time.Time) []*Theme {
+	today := dayOf(now)
+	var out []*Theme
+	for _, t := range d.SortedThemes() {
+		if !d.ThemeAvailableFrom(t, now).After(today) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SetTheme plays a catalogue theme in the session.
+func (s *Session) SetTheme(t *Theme) {
+	s.Theme = t.Name
+	s.ThemeID = t.ID
+}
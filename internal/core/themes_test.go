@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAvailableThemesRespectsCooldown(t *testing.T) {
+	d := NewData()
+	covers, _ := d.AddTheme("Covers", 4)
+	eighties, _ := d.AddTheme("80s", 0)
+	if _, err := d.AddTheme("covers", 1); !errors.Is(err, ErrThemeExists) {
+		t.Fatalf("expected ErrThemeExists, got %v", err)
+	}
+
+	played := time.Date(2026, 9, 1, 10, 0, 0, 0, time.Local)
+	d.SessionOn(played).SetTheme(covers)
+	d.SessionOn(played.AddDate(0, 0, 7)).SetTheme(eighties)
+
+	names := func(ts []*Theme) []string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.Name)
+		}
+		return out
+	}
+	if got := names(d.AvailableThemes(played.AddDate(0, 0, 21))); len(got) != 1 || got[0] != "80s" {
+		t.Fatalf("three weeks later: available = %v, want [80s]", got)
+	}
+	if got := names(d.AvailableThemes(played.AddDate(0, 0, 28))); len(got) != 2 {
+		t.Fatalf("four weeks later: available = %v, want both", got)
+	}
+	// drawing again on the same day does not put the theme into cooldown
+	if got := names(d.AvailableThemes(played)); len(got) != 2 {
+		t.Fatalf("same day: available = %v, want both", got)
+	}
+	if last := d.ThemeLastUsed(covers); !last.Equal(dayOf(played)) {
+		t.Fatalf("ThemeLastUsed = %v", last)
+	}
+	// the list of themes goes by the same rule as the draw
+	if from := d.ThemeAvailableFrom(covers, played); !from.IsZero() {
+		t.Errorf("same day: available from %v, want now", from)
+	}
+	if from := d.ThemeAvailableFrom(covers, played.AddDate(0, 0, 21)); !from.Equal(dayOf(played).AddDate(0, 0, 28)) {
+		t.Errorf("three weeks later: available from %v, want four weeks after it was played", from)
+	}
+}
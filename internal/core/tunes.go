@@ -0,0 +1,120 @@
+package core
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TuneThemes returns the distinct themes used by tunes, sorted.
+func (d *Data) TuneThemes() []string {
+	seen := make(map[string]bool)
+	var themes []string
+	for _, t := range d.Tunes {
+		key := strings.ToLower(t.Theme)
+		if t.Theme == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		themes = append(themes, t.Theme)
+	}
+	sort.Slice(themes, func(i, j int) bool { return strings.ToLower(themes[i]) < strings.ToLower(themes[j]) })
+	return themes
+}
+
+// TunesWithTheme returns the tunes of the given theme, ignoring case.
+// An empty theme returns all tunes.
+func (d *Data) TunesWithTheme(theme string) []Tune {
+	if theme == "" {
+		return d.Tunes
+	}
+	var out []Tune
+	for _, t := range d.Tunes {
+		if strings.EqualFold(t.Theme, theme) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+var platformHosts = map[string]string{
+	"youtube.com":    "YouTube",
+	"youtu.be":       "YouTube",
+	"soundcloud.com": "SoundCloud",
+	"spotify.com":    "Spotify",
+	"bandcamp.com":   "Bandcamp",
+	"vimeo.com":      "Vimeo",
+	"deezer.com":     "Deezer",
+	"apple.com":      "Apple Music",
+}
+
+// Platform names where the tune is hosted, e.g. "YouTube" or the link's host.
+func (t Tune) Platform() string {
+	if t.ID != "" && t.Provider == "youtube" {
+		return "YouTube"
+	}
+	u, err := url.Parse(strings.TrimSpace(t.Link))
+	if err != nil || u.Host == "" {
+		return "other"
+	}
+	host := strings.ToLower(u.Hostname())
+	for suffix, name := range platformHosts {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return name
+		}
+	}
+	return strings.TrimPrefix(host, "www.")
+}
+
+// Title is the video title, or the short link for tunes without a name.
+func (t Tune) Title() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return linkDisplay(t.Link)
+}
+
+// linkDisplay returns a concise representation of the link for table view.
+// For valid YouTube https links, it shows youtu.be/{id}. Otherwise host+path.
+func linkDisplay(link string) string {
+	if link == "" {
+		return ""
+	}
+	if strings.HasPrefix(link, "https://www.youtube.") || strings.HasPrefix(link, "https://youtu.be/") || strings.Contains(link, "youtube.com") {
+		if i := strings.Index(link, "watch?v="); i != -1 {
+			id := link[i+8:]
+			if j := strings.IndexAny(id, "&#?"); j != -1 {
+				id = id[:j]
+			}
+			if id != "" {
+				return "youtu.be/" + id
+			}
+		}
+		if i := strings.Index(link, "/shorts/"); i != -1 {
+			id := link[i+8:]
+			if j := strings.Index(id, "/"); j != -1 {
+				id = id[:j]
+			}
+			if id != "" {
+				return "youtu.be/" + id
+			}
+		}
+		if strings.HasPrefix(link, "https://youtu.be/") {
+			id := strings.TrimPrefix(link, "https://youtu.be/")
+			if j := strings.Index(id, "?"); j != -1 {
+				id = id[:j]
+			}
+			if id != "" {
+				return "youtu.be/" + id
+			}
+		}
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+	host := strings.ToLower(u.Host)
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return host + u.EscapedPath()
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeliverTuneTakesSessionTheme(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	now := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	s := d.SessionOn(now)
+	s.Theme = "Home country"
+	d.DeliverTune(s.AddDraw(ann.ID, now), Tune{ID: "a"})
+	d.DeliverTune(s.AddDraw(ann.ID, now), Tune{ID: "b", Theme: "Covers"})
+
+	if d.Tunes[0].Theme != "Home country" || d.Tunes[1].Theme != "Covers" {
+		t.Fatalf("unexpected themes: %+v", d.Tunes)
+	}
+}
+
+func TestTunesWithTheme(t *testing.T) {
+	d := NewData()
+	d.Tunes = []Tune{{ID: "a", Theme: "Covers"}, {ID: "b"}, {ID: "c", Theme: "covers"}, {ID: "d", Theme: "80s"}}
+
+	if got := d.TuneThemes(); !reflect.DeepEqual(got, []string{"80s", "Covers"}) {
+		t.Fatalf("TuneThemes = %v", got)
+	}
+	var ids []string
+	for _, tune := range d.TunesWithTheme("COVERS") {
+		ids = append(ids, tune.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "c"}) {
+		t.Fatalf("TunesWithTheme = %v", ids)
+	}
+	if got := len(d.TunesWithTheme("")); got != 4 {
+		t.Fatalf("empty theme returned %d tunes, want all 4", got)
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	cases := map[string]Tune{
+		"YouTube":     {ID: "abc", Provider: "youtube"},
+		"Bandcamp":    {Link: "https://artist.bandcamp.com/track/x"},
+		"Spotify":     {Link: "https://open.spotify.com/track/1"},
+		"example.org": {Link: "https://www.example.org/song.mp3"},
+		"other":       {Link: "not a link"},
+	}
+	for want, tune := range cases {
+		if got := tune.Platform(); got != want {
+			t.Errorf("%+v.Platform() = %q, want %q", tune, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidRating = errors.New("rating must be 1-5 stars, up or down")
+	ErrTuneNotFound  = errors.New("tune not found")
+)
+
+// Thumbs up and down are stored as star ratings so that both kinds of votes
+// add up to one score.
+const (
+	ThumbsUp   = 5
+	ThumbsDown = 1
+)
+
+// ParseRating reads "1".."5", "up"/"+" or "down"/"-" into stars.
+func ParseRating(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "up", "+", "+1", "👍":
+		return ThumbsUp, nil
+	case "down", "-", "-1", "👎":
+		return ThumbsDown, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 || n > 5 {
+		return 0, ErrInvalidRating
+	}
+	return n, nil
+}
+
+// Score returns the average rating of the tune, or 0 without votes.
+func (t Tune) Score() float64 {
+	if len(t.Votes) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, v := range t.Votes {
+		sum += v
+	}
+	return float64(sum) / float64(len(t.Votes))
+}
+
+// ScoreLabel formats the score for lists, e.g. "4.5 (2)".
+func (t Tune) ScoreLabel() string {
+	if len(t.Votes) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f (%d)", t.Score(), len(t.Votes))
+}
+
+// Vote records the participant's rating of the tune at index i, replacing an
+// earlier vote. Zero stars withdraws the vote.
+func (d *Data) Vote(i int, participantID string, stars int) error {
+	if i < 0 || i >= len(d.Tunes) {
+		return ErrTuneNotFound
+	}
+	if d.Participant(participantID) == nil {
+		return ErrParticipantNotFound
+	}
+	t := &d.Tunes[i]
+	if stars == 0 {
+		delete(t.Votes, participantID)
+		return nil
+	}
+	if stars < 1 || stars > 5 {
+		return ErrInvalidRating
+	}
+	if t.Votes == nil {
+		t.Votes = make(map[string]int)
+	}
+	t.Votes[participantID] = stars
+	return nil
+}
+
+// FindTune resolves a reference given on the command line or by an
+// integration: a YouTube video ID or a 1-based position in the tune list.
+// If the same video was added more than once, the latest entry wins.
+func (d *Data) FindTune(ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	for i := len(d.Tunes) - 1; i >= 0; i-- {
+		if ref != "" && d.Tunes[i].ID == ref {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(d.Tunes) {
+		return n - 1, nil
+	}
+	return -1, ErrTuneNotFound
+}
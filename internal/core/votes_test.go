@@ -0,0 +1,80 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRating(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"5", 5, true},
+		{" 1 ", 1, true},
+		{"up", ThumbsUp, true},
+		{"DOWN", ThumbsDown, true},
+		{"0", 0, false},
+		{"6", 0, false},
+		{"great", 0, false},
+	}
+	for _, tc := range cases {
+		got, err := ParseRating(tc.in)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("ParseRating(%q) = %d, %v; want %d, ok=%v", tc.in, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestVoteOncePerParticipant(t *testing.T) {
+	d := NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	d.Tunes = []Tune{{ID: "a"}, {ID: "b"}}
+
+	if err := d.Vote(0, ann.ID, 2); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+	if err := d.Vote(0, ann.ID, 4); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+	if err := d.Vote(0, bob.ID, ThumbsUp); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+	if got := d.Tunes[0].Score(); got != 4.5 || len(d.Tunes[0].Votes) != 2 {
+		t.Fatalf("score = %v with %d votes, want 4.5 with 2", got, len(d.Tunes[0].Votes))
+	}
+	if err := d.Vote(0, bob.ID, 0); err != nil || len(d.Tunes[0].Votes) != 1 {
+		t.Fatalf("withdrawing failed: %v, votes %v", err, d.Tunes[0].Votes)
+	}
+	if err := d.Vote(1, "nobody", 3); !errors.Is(err, ErrParticipantNotFound) {
+		t.Fatalf("expected ErrParticipantNotFound, got %v", err)
+	}
+	if err := d.Vote(5, ann.ID, 3); !errors.Is(err, ErrTuneNotFound) {
+		t.Fatalf("expected ErrTuneNotFound, got %v", err)
+	}
+}
+
+func TestFindTuneAndSortScore(t *testing.T) {
+	d := NewData()
+	d.Tunes = []Tune{
+		{ID: "a", Votes: map[string]int{"x": 2}},
+		{ID: "b"},
+		{ID: "c", Votes: map[string]int{"x": 5}},
+	}
+	if i, err := d.FindTune("c"); err != nil || i != 2 {
+		t.Fatalf("FindTune(c) = %d, %v", i, err)
+	}
+	if i, err := d.FindTune("2"); err != nil || i != 1 {
+		t.Fatalf("FindTune(2) = %d, %v", i, err)
+	}
+	if _, err := d.FindTune("zzz"); !errors.Is(err, ErrTuneNotFound) {
+		t.Fatalf("expected ErrTuneNotFound, got %v", err)
+	}
+
+	order := d.FilterTunes(TuneFilter{}, SortScore)
+	if ids := d.Tunes[order[0]].ID + d.Tunes[order[1]].ID + d.Tunes[order[2]].ID; ids != "cab" {
+		t.Fatalf("unexpected order: %s", ids)
+	}
+}
@@ -0,0 +1,275 @@
+package i18n
+
+// German is the German translation. Tuesday is Dienstag, so Tunesday
+// becomes Tunestag.
+var German = &Locale{
+	Tag:    "de",
+	Name:   "Deutsch",
+	plural: oneOther,
+	months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+		"Juli", "August", "September", "Oktober", "November", "Dezember"},
+	weekdays:       [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	dateLayout:     "02.01.2006",
+	longDateLayout: "Monday, 2. January 2006",
+	monthLayout:    "January 2006",
+	dateInput:      "02.01.2006",
+
+	plurals: map[string][]string{
+		"cooldown: %d week":             {"Pause: %d Woche", "Pause: %d Wochen"},
+		"owes %d tune":                  {"schuldet %d Tune", "schuldet %d Tunes"},
+		"Remove %s and their %d tune?":  {"%s und den %d mitgebrachten Tune entfernen?", "%s und die %d mitgebrachten Tunes entfernen?"},
+		"The data file has %d problem.": {"Die Datendatei hat %d Problem.", "Die Datendatei hat %d Probleme."},
+		"%d draw recorded, volunteers and legacy counts left out": {
+			"%d Ziehung erfasst, ohne Freiwillige und Altbestände",
+			"%d Ziehungen erfasst, ohne Freiwillige und Altbestände",
+		},
+		"%d day (%s)": {"%d Tag (%s)", "%d Tage (%s)"},
+	},
+
+	messages: map[string]string{
+		// headers
+		"IT'S...":                 "ES IST...",
+		"HAPPY TUNESDAY !!":       "FROHEN TUNESTAG !!",
+		"!! IT'S NOT TUNESDAY !!": "!! KEIN TUNESTAG !!",
+		"TUNESDAY RADIO":          "TUNESTAG RADIO",
+		"♫ It's Tunesday! ♫":      "♫ Es ist Tunestag! ♫",
+		"♫ It's not Tunesday! ♫":  "♫ Heute ist kein Tunestag! ♫",
+		"♫ Tunesday Radio ♫":      "♫ Tunestag Radio ♫",
+		"Today's theme: %s":       "Heutiges Thema: %s",
+
+		// main menu
+		"Tunesday Menu":                        "Tunestag-Menü",
+		"Tunesday Menu · %s":                   "Tunestag-Menü · %s",
+		"Which team?":                          "Welches Team?",
+		"Select todays tune provider":          "Heutige Tune-Auswahl auslosen",
+		"Plan todays round (theme, providers)": "Heutige Runde planen (Thema, Anzahl)",
+		"Manually add a tune to list":          "Tune von Hand hinzufügen",
+		"Get complete list of tunes":           "Alle Tunes anzeigen",
+		"Rate tunes":                           "Tunes bewerten",
+		"Statistics":                           "Statistik",
+		"Manage Tunesday participants":         "Teilnehmende verwalten",
+		"Get youtube playlist link":            "YouTube-Playlist-Link erstellen",
+		"Exit":                                 "Beenden",
+		"Goodbye!":                             "Tschüss!",
+		"Undo last action":                     "Letzte Aktion rückgängig machen",
+		"Undo last action (%s)":                "Letzte Aktion rückgängig machen (%s)",
+		"Redo":                                 "Wiederholen",
+		"Redo (%s)":                            "Wiederholen (%s)",
+
+		// undo and confirmations
+		"Nothing to undo.":     "Nichts rückgängig zu machen.",
+		"Nothing to redo.":     "Nichts zu wiederholen.",
+		"Could not undo: %v":   "Rückgängig machen fehlgeschlagen: %v",
+		"Could not redo: %v":   "Wiederholen fehlgeschlagen: %v",
+		"Undone: %s (%s)":      "Rückgängig gemacht: %s (%s)",
+		"Redone: %s":           "Wiederholt: %s",
+		"No, keep it":          "Nein, behalten",
+		"Yes, remove it":       "Ja, entfernen",
+		"Yes, remove %s":       "Ja, %s entfernen",
+		"Remove %s?":           "%s entfernen?",
+		"Remove the theme %s?": "Das Thema %s entfernen?",
+
+		// archive
+		"Archive (left the team)":                                          "Archivieren (hat das Team verlassen)",
+		"Restore from archive":                                             "Aus dem Archiv zurückholen",
+		"Archive %s instead (keeps their history)":                         "%s stattdessen archivieren (Verlauf bleibt)",
+		"%s is archived. Restore them from the archive instead.":           "%s ist archiviert. Bitte stattdessen aus dem Archiv zurückholen.",
+		"%s is archived. Their tunes stay in the list and the statistics.": "%s ist archiviert. Die Tunes bleiben in der Liste und der Statistik.",
+		"Archived:":           "Archiviert:",
+		"archived":            "archiviert",
+		"archived %s":         "archiviert am %s",
+		"%s (archived)":       "%s (archiviert)",
+		"Who left the team?":  "Wer hat das Team verlassen?",
+		"Who is back?":        "Wer ist zurück?",
+		"Nobody is archived.": "Niemand ist archiviert.",
+		"Welcome back, %s!":   "Willkommen zurück, %s!",
+
+		// menus and pages
+		"↑ %d more":   "↑ %d weitere",
+		"↓ %d more":   "↓ %d weitere",
+		"Filter: %s":  "Filter: %s",
+		"No matches.": "Keine Treffer.",
+		"↑↓ jk move · g G ends · 1-9 pick · / filter · Enter select · Esc back": "↑↓ jk · g G Anfang/Ende · 1-9 wählen · / filtern · Enter OK · Esc zurück",
+		"↑↓ move · Enter select · Backspace edit · Esc clear filter":            "↑↓ bewegen · Enter wählen · Rücktaste ändern · Esc Filter löschen",
+		"Press Enter to continue...":                      "Weiter mit Enter...",
+		"Run \"tunesday doctor\" to see and repair them.": "„tunesday doctor“ zeigt und repariert sie.",
+		"Back":   "Zurück",
+		"Add":    "Hinzufügen",
+		"Remove": "Entfernen",
+		"List":   "Auflisten",
+		"Rename": "Umbenennen",
+		"none":   "keins",
+
+		// the draw
+		"No participants available.": "Keine Teilnehmenden vorhanden.",
+		"All participants are deactivated. Activate at least one to select a provider.": "Alle Teilnehmenden sind deaktiviert. Aktiviere mindestens eine Person, um auszulosen.",
+		"What now?":                    "Und jetzt?",
+		"Provider %d of %d. What now?": "Auswahl %d von %d. Und jetzt?",
+		"%s owes a tune from an earlier Tunesday. %s": "%s schuldet noch einen Tune von einem früheren Tunestag. %s",
+		"Add the tune":                    "Tune hinzufügen",
+		"Re-roll (excluding %s)":          "Neu auslosen (ohne %s)",
+		"Swap with volunteer":             "Mit freiwilliger Person tauschen",
+		"%s passes, owes next week":       "%s setzt aus und liefert nächste Woche",
+		"Nobody left to draw today.":      "Heute ist niemand mehr übrig.",
+		"Who volunteers instead of %s?":   "Wer springt für %s ein?",
+		"Selecting today's provider…":     "Heutige Auswahl wird ausgelost…",
+		"Selecting today's %d providers…": "Heutige %d Auswahlen werden ausgelost…",
+		"%s is today's tune provider!!":   "%s bringt heute den Tune mit!!",
+		"is today's tune provider!!":      "bringt heute den Tune mit!!",
+		"Today's tune provider is: %s":    "Heute bringt den Tune mit: %s",
+		"Theme: %s":                       "Thema: %s",
+		"Skipped.":                        "Übersprungen.",
+		"Failed to fetch title: %v":       "Titel konnte nicht geladen werden: %v",
+		"Added: %s":                       "Hinzugefügt: %s",
+		"Added.":                          "Hinzugefügt.",
+		"Link: ":                          "Link: ",
+		"Paste the tune link (YouTube https://…) or press Enter to cancel:":                "Tune-Link einfügen (YouTube https://…) oder mit Enter abbrechen:",
+		"Only https:// YouTube links are supported for automatic title fetch.":             "Titel werden nur für https://-YouTube-Links automatisch geladen.",
+		"Paste the link (any), and the title shown in the list will be the URL host/path.": "Beliebigen Link einfügen; in der Liste erscheinen dann Host und Pfad der URL.",
+
+		// planning and themes
+		"Plan todays round (theme: %s, providers: %d)":         "Heutige Runde planen (Thema: %s, Anzahl: %d)",
+		"Draw a theme from the catalogue":                      "Thema aus dem Katalog auslosen",
+		"Set theme by hand":                                    "Thema von Hand setzen",
+		"Clear theme":                                          "Thema entfernen",
+		"Number of providers":                                  "Anzahl Tunes",
+		"Manage theme catalogue":                               "Themenkatalog verwalten",
+		"Theme: ":                                              "Thema: ",
+		"How many providers today? (current: %d): ":            "Wie viele Tunes heute? (aktuell: %d): ",
+		"Please enter a number of at least 1.":                 "Bitte eine Zahl ab 1 eingeben.",
+		"The theme catalogue is empty. Add some themes first.": "Der Themenkatalog ist leer. Lege zuerst ein paar Themen an.",
+		"All themes are cooling down. Add more themes or shorten their cooldowns.": "Alle Themen pausieren gerade. Lege mehr Themen an oder verkürze die Pausen.",
+		"Drawing today's theme…":                "Heutiges Thema wird ausgelost…",
+		"Change cooldown":                       "Pause ändern",
+		"Theme name: ":                          "Name des Themas: ",
+		"Theme already exists.":                 "Das Thema gibt es schon.",
+		"Theme added.":                          "Thema hinzugefügt.",
+		"Select theme to remove":                "Welches Thema entfernen?",
+		"Select theme":                          "Thema wählen",
+		"Removed.":                              "Entfernt.",
+		"No themes.":                            "Keine Themen.",
+		"Themes:":                               "Themen:",
+		"never played":                          "noch nie gespielt",
+		"last played %s":                        "zuletzt am %s",
+		"last played %s, cooling down until %s": "zuletzt am %s, pausiert bis %s",
+		"Cooldown in weeks (Enter for %d): ":    "Pause in Wochen (Enter für %d): ",
+		"Please enter a number of weeks.":       "Bitte eine Anzahl Wochen eingeben.",
+		"All tunes":                             "Alle Tunes",
+		"Playlist of which tunes?":              "Playlist aus welchen Tunes?",
+		"No valid YouTube video IDs found to build a playlist (no tunes with titles).": "Keine gültigen YouTube-Video-IDs für eine Playlist gefunden (keine Tunes mit Titel).",
+		"Links for pasting: (https://www.terrific.tools/youtube/playlist-generator)":   "Links zum Einfügen: (https://www.terrific.tools/youtube/playlist-generator)",
+
+		// rating
+		"No tunes yet.":  "Noch keine Tunes.",
+		"Who is voting?": "Wer bewertet?",
+		"not rated":      "nicht bewertet",
+		"you: %s":        "du: %s",
+		"%s, score %s":   "%s, Wertung %s",
+		"%s, pick a tune to rate (Esc when done)": "%s, wähle einen Tune zum Bewerten (Esc wenn fertig)",
+		"Rate %s":          "%s bewerten",
+		"👍 Thumbs up":      "👍 Daumen hoch",
+		"👎 Thumbs down":    "👎 Daumen runter",
+		"Withdraw my vote": "Meine Bewertung zurückziehen",
+
+		// participants
+		"Activate/Deactivate":                         "Aktivieren/Deaktivieren",
+		"Enter participant name: ":                    "Name der Person: ",
+		"Participant already exists.":                 "Die Person gibt es schon.",
+		"Participant added.":                          "Person hinzugefügt.",
+		"Select participant to rename":                "Wen umbenennen?",
+		"New name for %s: ":                           "Neuer Name für %s: ",
+		"Another participant already uses that name.": "Den Namen trägt schon jemand anderes.",
+		"%s is now %s.":                               "%s heißt jetzt %s.",
+		"Select participant to remove":                "Wen entfernen?",
+		"Select participant to toggle activation":     "Wen aktivieren oder deaktivieren?",
+		"No participants.":                            "Keine Teilnehmenden.",
+		"Participants:":                               "Teilnehmende:",
+		"active":                                      "aktiv",
+		"deactivated":                                 "deaktiviert",
+		"%s deactivated.":                             "%s deaktiviert.",
+		"%s activated.":                               "%s aktiviert.",
+		"tunes: %d, drawn: %d, skipped: %d, swapped: %d, volunteered: %d, %s": "Tunes: %d, gezogen: %d, übersprungen: %d, getauscht: %d, eingesprungen: %d, %s",
+
+		// statistics
+		"Leaderboard":              "Bestenliste",
+		"Tunes over time":          "Tunes im Zeitverlauf",
+		"Fair share of draws":      "Gerechte Verteilung der Ziehungen",
+		"Days since last tune":     "Tage seit dem letzten Tune",
+		"Platforms":                "Plattformen",
+		"Tunesday Wrapped %d":      "Tunestag-Jahresrückblick %d",
+		"Participant":              "Person",
+		"Tunes":                    "Tunes",
+		"Drawn":                    "Gezogen",
+		"Skipped":                  "Übersprungen",
+		"Passed":                   "Ausgesetzt",
+		"Volunteered":              "Eingesprungen",
+		"Total":                    "Summe",
+		"Expected":                 "Erwartet",
+		"Fairness":                 "Fairness",
+		"▲ lucky":                  "▲ Glück gehabt",
+		"▼ spared":                 "▼ verschont",
+		"No draws yet.":            "Noch keine Ziehungen.",
+		"never":                    "nie",
+		"Tunes per month since %s": "Tunes pro Monat seit %s",
+		"Share of draws compared to a perfectly fair draw": "Anteil an den Ziehungen im Vergleich zu einer völlig gerechten Verlosung",
+		"Days since each participant last provided a tune": "Tage, seit jede Person zuletzt einen Tune mitgebracht hat",
+
+		// tune list
+		"Tunes: %d of %d, %s": "Tunes: %d von %d, %s",
+		"Search: %s":          "Suche: %s",
+		"Title":               "Titel",
+		"By":                  "Von",
+		"Date":                "Datum",
+		"Score":               "Wertung",
+		"No tunes match.":     "Keine passenden Tunes.",
+		"Page %d/%d":          "Seite %d/%d",
+		"Type to search · ↑↓ PgUp PgDn · Enter details · Tab filters & sort · Esc back": "Tippen zum Suchen · ↑↓ Bild↑ Bild↓ · Enter Details · Tab Filter & Sortierung · Esc zurück",
+		"by %s":                 "von %s",
+		"on %s":                 "auf %s",
+		"theme %s":              "Thema %s",
+		"all dates":             "alle Daten",
+		"since %s":              "seit %s",
+		"until %s":              "bis %s",
+		"%s to %s":              "%s bis %s",
+		"any rating":            "jede Wertung",
+		"unrated":               "unbewertet",
+		"rated %.0f+":           "Wertung ab %.0f",
+		"everyone":              "alle",
+		"all":                   "alle",
+		"Filter and sort tunes": "Tunes filtern und sortieren",
+		"Sort: %s":              "Sortierung: %s",
+		"Date range: %s":        "Zeitraum: %s",
+		"Participant: %s":       "Person: %s",
+		"Platform: %s":          "Plattform: %s",
+		"Rating: %s":            "Wertung: %s",
+		"Clear filters":         "Filter zurücksetzen",
+		"Back to list":          "Zurück zur Liste",
+		"Sort by":               "Sortieren nach",
+		"From":                  "Von",
+		"To":                    "Bis",
+		"Everyone":              "Alle",
+		"Tunes brought by":      "Tunes mitgebracht von",
+		"Platform":              "Plattform",
+		"All":                   "Alle",
+		"Rating":                "Wertung",
+		"Theme":                 "Thema",
+		"%s (e.g. %s, Enter keeps %s, - for none): ": "%s (z. B. %s, Enter behält %s, - für keins): ",
+		"Please enter a date like %s.":               "Bitte ein Datum wie %s eingeben.",
+		"Newest first":                               "Neueste zuerst",
+		"Oldest first":                               "Älteste zuerst",
+		"Provider":                                   "Person",
+
+		// tune details
+		"Link":      "Link",
+		"Channel":   "Kanal",
+		"Length":    "Länge",
+		"Added":     "Datum",
+		"Tags":      "Tags",
+		"unknown":   "unbekannt",
+		"Edit tags": "Tags bearbeiten",
+		"Tags, comma separated (currently: %s): ": "Tags, durch Kommas getrennt (aktuell: %s): ",
+
+		// skins
+		"Skin: %s": "Skin: %s",
+	},
+}
@@ -0,0 +1,211 @@
+// Package i18n translates the text of the user interface.
+//
+// Messages are looked up by their English text, so calls read like the
+// English UI and English needs no catalogue:
+//
+//	i18n.T("Participant added.")
+//	i18n.T("%s is now %s.", old, name)
+//	i18n.N(n, "owes %d tune", "owes %d tunes", n)
+//
+// A translation may reorder its arguments with explicit indexes such as
+// %[2]s.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale is a language the UI can be shown in.
+type Locale struct {
+	Tag  string // language code, e.g. "de"
+	Name string // the language's name in itself
+
+	messages map[string]string
+	// plurals holds the plural forms of messages keyed by the English
+	// singular, in the order plural returns.
+	plurals map[string][]string
+	// plural returns which plural form to use for a count of n.
+	plural func(n int) int
+
+	months   [12]string
+	weekdays [7]string // Sunday first, like time.Weekday
+	// dateLayout and longDateLayout are time layouts; their month and
+	// weekday names are replaced by the locale's.
+	dateLayout     string
+	longDateLayout string
+	monthLayout    string
+	// dateInput is the layout dates are typed in, besides ISO 2006-01-02.
+	dateInput string
+}
+
+// oneOther is the plural rule of English, German and many more: one form for
+// exactly one and another for everything else.
+func oneOther(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// Locales are the supported languages by tag.
+var Locales = map[string]*Locale{
+	"en": English,
+	"de": German,
+}
+
+// English is the language the messages are written in.
+var English = &Locale{
+	Tag:    "en",
+	Name:   "English",
+	plural: oneOther,
+	months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	weekdays:       [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	dateLayout:     "Jan 2, 2006",
+	longDateLayout: "Monday, January 2, 2006",
+	monthLayout:    "January 2006",
+	dateInput:      "2006-01-02",
+}
+
+var current = English
+
+// Current returns the locale in use.
+func Current() *Locale { return current }
+
+// Use switches to the locale for tag. Region and encoding are ignored, so
+// "de_AT.UTF-8" and "de-CH" both pick German; an empty tag picks English.
+func Use(tag string) error {
+	if tag == "" {
+		current = English
+		return nil
+	}
+	l, ok := Locales[language(tag)]
+	if !ok {
+		return fmt.Errorf("unsupported language %q (supported: %s)", tag, strings.Join(Tags(), ", "))
+	}
+	current = l
+	return nil
+}
+
+// Tags returns the tags of the supported languages, sorted.
+func Tags() []string {
+	tags := make([]string, 0, len(Locales))
+	for tag := range Locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// language returns the language part of a locale name like "de_DE.UTF-8".
+func language(tag string) string {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, "_-.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// Detect picks the language from the environment the way POSIX programs do:
+// LC_ALL, then LC_MESSAGES, then LANG. Languages without a translation fall
+// back to English.
+func Detect(getenv func(string) string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := getenv(key)
+		if v == "" {
+			continue
+		}
+		if _, ok := Locales[language(v)]; ok {
+			return language(v)
+		}
+		return English.Tag
+	}
+	return English.Tag
+}
+
+// T translates msg and formats it with args like fmt.Sprintf.
+func T(msg string, args ...any) string { return current.T(msg, args...) }
+
+// N translates the singular or plural of a message for a count of n and
+// formats it with args.
+func N(n int, one, other string, args ...any) string { return current.N(n, one, other, args...) }
+
+// Date formats a day briefly, e.g. "Sep 8, 2026" or "08.09.2026".
+func Date(t time.Time) string { return current.format(t, current.dateLayout) }
+
+// LongDate formats a day with its weekday, e.g. "Tuesday, September 8, 2026".
+func LongDate(t time.Time) string { return current.format(t, current.longDateLayout) }
+
+// MonthYear formats a month, e.g. "September 2026".
+func MonthYear(t time.Time) string { return current.format(t, current.monthLayout) }
+
+// Month returns the name of month m.
+func Month(m time.Month) string { return current.months[m-1] }
+
+// DateHint is an example of how to type a date, e.g. "31.01.2026".
+func DateHint() string { return time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC).Format(current.dateInput) }
+
+// ParseDate reads a day typed as YYYY-MM-DD or in the locale's own way.
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(current.dateInput, s, time.Local)
+}
+
+// T translates msg and formats it with args like fmt.Sprintf.
+func (l *Locale) T(msg string, args ...any) string {
+	if tr, ok := l.messages[msg]; ok {
+		msg = tr
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N translates the singular or plural of a message for a count of n and
+// formats it with args.
+func (l *Locale) N(n int, one, other string, args ...any) string {
+	forms := []string{one, other}
+	if tr, ok := l.plurals[one]; ok {
+		forms = tr
+	}
+	msg := forms[len(forms)-1]
+	if i := l.plural(n); i < len(forms) {
+		msg = forms[i]
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// format formats t with layout, swapping Go's English month and weekday
+// names for the locale's.
+func (l *Locale) format(t time.Time, layout string) string {
+	names := []struct{ token, mark, name string }{
+		{"January", "\x00M\x00", l.months[t.Month()-1]},
+		{"Jan", "\x00m\x00", abbrev(l.months[t.Month()-1])},
+		{"Monday", "\x00W\x00", l.weekdays[t.Weekday()]},
+	}
+	for _, n := range names {
+		layout = strings.ReplaceAll(layout, n.token, n.mark)
+	}
+	out := t.Format(layout)
+	for _, n := range names {
+		out = strings.ReplaceAll(out, n.mark, n.name)
+	}
+	return out
+}
+
+func abbrev(name string) string {
+	r := []rune(name)
+	if len(r) > 3 {
+		r = r[:3]
+	}
+	return string(r)
+}
@@ -0,0 +1,181 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func use(t *testing.T, tag string) {
+	t.Helper()
+	if err := Use(tag); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { current = English })
+}
+
+func TestUseAndDetect(t *testing.T) {
+	for _, tag := range []string{"de", "de_AT.UTF-8", "DE-ch"} {
+		use(t, tag)
+		if Current() != German {
+			t.Errorf("%s: got %s", tag, Current().Tag)
+		}
+	}
+	if err := Use("fr"); err == nil || !strings.Contains(err.Error(), "de, en") {
+		t.Errorf("unsupported language error = %v", err)
+	}
+
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "C"}, "en"},
+		{map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "de_CH"}, "de"},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, "en"},
+	}
+	for _, c := range cases {
+		if got := Detect(func(k string) string { return c.env[k] }); got != c.want {
+			t.Errorf("%v: got %s, want %s", c.env, got, c.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := T("%s is now %s.", "Ann", "Anna"); got != "Ann is now Anna." {
+		t.Errorf("English: %q", got)
+	}
+	use(t, "de")
+	if got := T("%s is now %s.", "Ann", "Anna"); got != "Ann heißt jetzt Anna." {
+		t.Errorf("German: %q", got)
+	}
+	if got := T("not in the catalogue"); got != "not in the catalogue" {
+		t.Errorf("missing messages should stay English, got %q", got)
+	}
+	for n, want := range map[int]string{0: "schuldet 0 Tunes", 1: "schuldet 1 Tune", 2: "schuldet 2 Tunes"} {
+		if got := N(n, "owes %d tune", "owes %d tunes", n); got != want {
+			t.Errorf("N(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	day := time.Date(2026, 3, 3, 19, 0, 0, 0, time.UTC)
+	if got := Date(day) + " | " + LongDate(day) + " | " + MonthYear(day); got != "Mar 3, 2026 | Tuesday, March 3, 2026 | March 2026" {
+		t.Errorf("English: %q", got)
+	}
+	use(t, "de")
+	if got := Date(day) + " | " + LongDate(day) + " | " + MonthYear(day); got != "03.03.2026 | Dienstag, 3. März 2026 | März 2026" {
+		t.Errorf("German: %q", got)
+	}
+	for _, in := range []string{"2026-03-03", "03.03.2026"} {
+		got, err := ParseDate(in)
+		if err != nil || got.Format("2006-01-02") != "2026-03-03" {
+			t.Errorf("ParseDate(%q) = %v, %v", in, got, err)
+		}
+	}
+	if DateHint() != "31.01.2026" {
+		t.Errorf("DateHint = %q", DateHint())
+	}
+}
+
+// indirect are messages translated through a variable rather than a literal
+// i18n.T call: the headers and the sort orders.
+var indirect = []string{
+	"IT'S...", "HAPPY TUNESDAY !!", "!! IT'S NOT TUNESDAY !!", "TUNESDAY RADIO",
+	"♫ It's Tunesday! ♫", "♫ It's not Tunesday! ♫", "♫ Tunesday Radio ♫",
+	"Newest first", "Oldest first", "Title", "Score", "Provider",
+}
+
+var (
+	callT = regexp.MustCompile(`i18n\.T\(\s*("(?:[^"\\]|\\.)*")`)
+	callN = regexp.MustCompile(`i18n\.N\([^,]+,\s*("(?:[^"\\]|\\.)*")`)
+	verb  = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*[\d.]*[a-zA-Z%]`)
+)
+
+// TestGermanIsComplete finds every message in the code and checks that the
+// German catalogue translates it with the same formatting verbs.
+func TestGermanIsComplete(t *testing.T) {
+	var msgs []string
+	single := map[string]bool{}
+	for _, m := range indirect {
+		single[m] = true
+	}
+	plural := map[string]bool{}
+	err := filepath.Walk("../..", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range callT.FindAllStringSubmatch(string(src), -1) {
+			s, _ := strconv.Unquote(m[1])
+			single[s] = true
+		}
+		for _, m := range callN.FindAllStringSubmatch(string(src), -1) {
+			s, _ := strconv.Unquote(m[1])
+			plural[s] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(single) < 100 {
+		t.Fatalf("found only %d messages, is the source scan broken?", len(single))
+	}
+	for m := range single {
+		msgs = append(msgs, m)
+	}
+	sort.Strings(msgs)
+	for _, m := range msgs {
+		tr, ok := German.messages[m]
+		if !ok {
+			t.Errorf("no German for %q", m)
+			continue
+		}
+		if !sameVerbs(m, tr) {
+			t.Errorf("%q and %q use different verbs", m, tr)
+		}
+	}
+	for m := range plural {
+		forms, ok := German.plurals[m]
+		if !ok || len(forms) != 2 {
+			t.Errorf("no German plural forms for %q", m)
+			continue
+		}
+		for _, f := range forms {
+			if !sameVerbs(m, f) {
+				t.Errorf("%q and %q use different verbs", m, f)
+			}
+		}
+	}
+}
+
+// sameVerbs reports whether both messages format the same kinds of
+// arguments, ignoring order when the translation numbers its arguments.
+func sameVerbs(a, b string) bool {
+	kinds := func(s string) []string {
+		var out []string
+		for _, v := range verb.FindAllString(s, -1) {
+			out = append(out, v[len(v)-1:])
+		}
+		if strings.Contains(s, "%[") {
+			sort.Strings(out)
+		}
+		return out
+	}
+	ka, kb := kinds(a), kinds(b)
+	if strings.Contains(b, "%[") {
+		sort.Strings(ka)
+	}
+	return strings.Join(ka, "") == strings.Join(kb, "")
+}
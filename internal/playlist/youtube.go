@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/kkdai/youtube/v2"
 )
@@ -14,6 +15,31 @@ type TitleProvider interface {
 	FetchTitle(ctx context.Context, linkOrID string) (string, error)
 }
 
+// VideoInfo is the metadata kept with a tune.
+type VideoInfo struct {
+	Title    string
+	Channel  string
+	Duration time.Duration
+}
+
+// InfoProvider is implemented by title providers that also know the channel
+// and length of a video.
+type InfoProvider interface {
+	FetchInfo(ctx context.Context, linkOrID string) (VideoInfo, error)
+}
+
+// PlaylistEntry is a video of a playlist.
+type PlaylistEntry struct {
+	ID string
+	VideoInfo
+}
+
+// PlaylistProvider is implemented by title providers that can list the
+// videos of a playlist.
+type PlaylistProvider interface {
+	Playlist(ctx context.Context, url string) ([]PlaylistEntry, error)
+}
+
 type YouTube struct{ c *youtube.Client }
 
 func NewYouTube() *YouTube { return &YouTube{c: &youtube.Client{}} }
@@ -63,6 +89,35 @@ func (y *YouTube) FetchTitle(ctx context.Context, linkOrID string) (string, erro
 	return strings.TrimSpace(v.Title), nil
 }
 
+func (y *YouTube) FetchInfo(ctx context.Context, linkOrID string) (VideoInfo, error) {
+	v, err := y.c.GetVideoContext(ctx, linkOrID)
+	if err != nil {
+		return VideoInfo{}, err
+	}
+	return VideoInfo{Title: strings.TrimSpace(v.Title), Channel: strings.TrimSpace(v.Author), Duration: v.Duration}, nil
+}
+
+func (y *YouTube) Playlist(ctx context.Context, url string) ([]PlaylistEntry, error) {
+	p, err := y.c.GetPlaylistContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PlaylistEntry, 0, len(p.Videos))
+	for _, v := range p.Videos {
+		out = append(out, PlaylistEntry{ID: v.ID, VideoInfo: VideoInfo{
+			Title:    strings.TrimSpace(v.Title),
+			Channel:  strings.TrimSpace(v.Author),
+			Duration: v.Duration,
+		}})
+	}
+	return out, nil
+}
+
+// WatchURL returns a link that plays the videos one after the other.
+func WatchURL(ids []string) string {
+	return "https://www.youtube.com/watch_videos?video_ids=" + strings.Join(ids, ",")
+}
+
 // StripTrackingParams removes common tracking/query parameters from a pasted YouTube URL.
 // Current behavior keeps everything before the first '&'. It is intentionally simple.
 func StripTrackingParams(link string) string {
@@ -0,0 +1,427 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"tunesday/internal/core"
+	"tunesday/internal/playlist"
+	"tunesday/internal/stats"
+)
+
+// participantJSON is a participant with the counts of their history.
+type participantJSON struct {
+	*core.Participant
+	Counts core.Counts `json:"counts"`
+}
+
+// findParticipant looks a participant up by ID, name or handle.
+func findParticipant(d *core.Data, ref string) (*core.Participant, error) {
+	if p := d.Participant(ref); p != nil {
+		return p, nil
+	}
+	if p := d.ParticipantByName(ref); p != nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: %s", core.ErrParticipantNotFound, ref)
+}
+
+func (s *Server) listParticipants(r *http.Request, d *core.Data) (reply, error) {
+	counts := d.Counts()
+	out := []participantJSON{}
+	for _, p := range d.SortedParticipants() {
+		out = append(out, participantJSON{p, counts[p.ID]})
+	}
+	return replyOK(out)
+}
+
+func (s *Server) getParticipant(r *http.Request, d *core.Data) (reply, error) {
+	p, err := findParticipant(d, r.PathValue("id"))
+	if err != nil {
+		return reply{}, err
+	}
+	return replyOK(participantJSON{p, d.Counts()[p.ID]})
+}
+
+func (s *Server) addParticipant(r *http.Request, d *core.Data) (string, reply, error) {
+	var body struct {
+		Name   string `json:"name"`
+		Handle string `json:"handle"`
+	}
+	if err := decode(r, &body); err != nil {
+		return "", reply{}, err
+	}
+	p, err := d.AddParticipant(body.Name)
+	if err != nil {
+		return "", reply{}, err
+	}
+	p.Handle = strings.TrimSpace(body.Handle)
+	res, _ := replyCreated(participantJSON{Participant: p})
+	return "add " + p.Name, res, nil
+}
+
+// changeParticipant renames, disables or archives a participant, or undoes
+// that; fields left out stay as they are.
+func (s *Server) changeParticipant(r *http.Request, d *core.Data) (string, reply, error) {
+	var body struct {
+		Name     *string `json:"name"`
+		Handle   *string `json:"handle"`
+		Disabled *bool   `json:"disabled"`
+		Archived *bool   `json:"archived"`
+	}
+	if err := decode(r, &body); err != nil {
+		return "", reply{}, err
+	}
+	p, err := findParticipant(d, r.PathValue("id"))
+	if err != nil {
+		return "", reply{}, err
+	}
+	action := "change " + p.Name
+	if body.Name != nil {
+		if err := d.RenameParticipant(p.ID, *body.Name); err != nil {
+			return "", reply{}, err
+		}
+	}
+	if body.Handle != nil {
+		p.Handle = strings.TrimSpace(*body.Handle)
+	}
+	if body.Disabled != nil {
+		p.Disabled = *body.Disabled
+	}
+	if body.Archived != nil && *body.Archived != p.Archived {
+		if *body.Archived {
+			d.ArchiveParticipant(p.ID, s.now())
+		} else {
+			d.RestoreParticipant(p.ID)
+		}
+	}
+	res, _ := replyOK(participantJSON{p, d.Counts()[p.ID]})
+	return action, res, nil
+}
+
+// removeParticipant removes a participant along with the tunes they brought.
+func (s *Server) removeParticipant(r *http.Request, d *core.Data) (string, reply, error) {
+	p, err := findParticipant(d, r.PathValue("id"))
+	if err != nil {
+		return "", reply{}, err
+	}
+	if err := d.RemoveParticipant(p.ID); err != nil {
+		return "", reply{}, err
+	}
+	return "remove " + p.Name, reply{status: http.StatusNoContent}, nil
+}
+
+// drawJSON is a draw with the name of whoever was drawn.
+type drawJSON struct {
+	*core.Draw
+	Name string `json:"name"`
+}
+
+// sessionJSON is a day's session with its draws.
+type sessionJSON struct {
+	Date  string     `json:"date"`
+	Theme string     `json:"theme,omitempty"`
+	Slots int        `json:"slots"`
+	Draws []drawJSON `json:"draws"`
+}
+
+func newSessionJSON(d *core.Data, day time.Time, s *core.Session) sessionJSON {
+	out := sessionJSON{Date: day.Format("2006-01-02"), Slots: 1, Draws: []drawJSON{}}
+	if s != nil {
+		out.Theme, out.Slots = s.Theme, s.ProviderSlots()
+		for _, dr := range s.Draws {
+			out.Draws = append(out.Draws, drawJSON{dr, d.ParticipantName(dr.ParticipantID)})
+		}
+	}
+	return out
+}
+
+func (s *Server) getSession(r *http.Request, d *core.Data) (reply, error) {
+	now := s.now()
+	return replyOK(newSessionJSON(d, now, d.LookupSession(now)))
+}
+
+// draw draws today's providers among the active participants who haven't
+// been drawn today yet, those who owe a tune first, and answers with today's
+// session. It draws as many as the session has slots unless the request
+// asks for a number.
+func (s *Server) draw(r *http.Request, d *core.Data) (string, reply, error) {
+	var body struct {
+		Count int `json:"count"`
+	}
+	if r.ContentLength != 0 {
+		if err := decode(r, &body); err != nil {
+			return "", reply{}, err
+		}
+	}
+	if body.Count < 0 {
+		return "", reply{}, errorf(http.StatusBadRequest, "count must be 1 or more")
+	}
+	now := s.now()
+	drawn := make(map[string]bool)
+	if session := d.LookupSession(now); session != nil {
+		for _, dr := range session.Draws {
+			drawn[dr.ParticipantID] = true
+		}
+	}
+	var candidates []*core.Participant
+	for _, p := range d.ActiveParticipants() {
+		if !drawn[p.ID] {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", reply{}, errorf(http.StatusConflict, "nobody left to draw today")
+	}
+
+	session := d.SessionOn(now)
+	n := body.Count
+	if n == 0 {
+		n = session.ProviderSlots()
+	}
+	var names []string
+	for _, i := range core.PickProviders(candidates, n, d.Counts(), rand.Shuffle) {
+		session.AddDraw(candidates[i].ID, now)
+		names = append(names, candidates[i].Name)
+	}
+	res, _ := replyCreated(newSessionJSON(d, now, session))
+	return "draw " + strings.Join(names, ", "), res, nil
+}
+
+// tuneJSON is a tune with its place in the list, which votes can refer to.
+type tuneJSON struct {
+	Number int `json:"number"`
+	core.Tune
+	By    string  `json:"by,omitempty"`
+	Score float64 `json:"score,omitempty"`
+}
+
+func newTuneJSON(d *core.Data, i int) tuneJSON {
+	t := d.Tunes[i]
+	return tuneJSON{Number: i + 1, Tune: t, By: d.ParticipantName(t.ParticipantID), Score: t.Score()}
+}
+
+var sorts = map[string]core.TuneSort{
+	"newest":   core.SortNewest,
+	"oldest":   core.SortOldest,
+	"title":    core.SortTitle,
+	"score":    core.SortScore,
+	"provider": core.SortProvider,
+}
+
+// listTunes lists the tunes, filtered and sorted like the tune list in the
+// app.
+func (s *Server) listTunes(r *http.Request, d *core.Data) (reply, error) {
+	q := r.URL.Query()
+	f := core.TuneFilter{Query: q.Get("q"), Theme: q.Get("theme"), Platform: q.Get("platform")}
+	if ref := q.Get("participant"); ref != "" {
+		p, err := findParticipant(d, ref)
+		if err != nil {
+			return reply{}, err
+		}
+		f.ParticipantID = p.ID
+	}
+	for name, day := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		if v := q.Get(name); v != "" {
+			t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+			if err != nil {
+				return reply{}, errorf(http.StatusBadRequest, "%s: want a date like 2006-01-02", name)
+			}
+			*day = t
+		}
+	}
+	by := core.SortNewest
+	if v := q.Get("sort"); v != "" {
+		var known bool
+		if by, known = sorts[v]; !known {
+			return reply{}, errorf(http.StatusBadRequest, "sort: want newest, oldest, title, score or provider")
+		}
+	}
+	out := []tuneJSON{}
+	for _, i := range d.FilterTunes(f, by) {
+		out = append(out, newTuneJSON(d, i))
+	}
+	return replyOK(out)
+}
+
+// addTune adds a tune. YouTube links get their title looked up, before the
+// data is changed since that can take a while. A tune by someone who was
+// drawn today and hasn't delivered yet answers their draw.
+func (s *Server) addTune(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Link        string   `json:"link"`
+		Name        string   `json:"name"`
+		Participant string   `json:"participant"`
+		Tags        []string `json:"tags"`
+	}
+	if err := decode(r, &body); err != nil {
+		respond(w, reply{}, err)
+		return
+	}
+	link := playlist.StripTrackingParams(strings.TrimSpace(body.Link))
+	if link == "" {
+		respond(w, reply{}, errorf(http.StatusBadRequest, "the tune has no link"))
+		return
+	}
+	t := core.Tune{Name: strings.TrimSpace(body.Name), Link: link, Provider: "manual", Tags: body.Tags, AddedAt: s.now()}
+	if id, ok := s.yt.NormalizeYouTubeID(link); ok {
+		t.ID, t.Provider = id, "youtube"
+		if err := s.fetchInfo(r, &t); err != nil {
+			respond(w, reply{}, errorf(http.StatusBadGateway, "looking up the title: %v", err))
+			return
+		}
+	}
+
+	res, err := s.change(r.Context(), func(d *core.Data) (string, reply, error) {
+		if body.Participant != "" {
+			p, err := findParticipant(d, body.Participant)
+			if err != nil {
+				return "", reply{}, err
+			}
+			t.ParticipantID = p.ID
+		}
+		session := d.LookupSession(t.AddedAt)
+		var draw *core.Draw
+		if session != nil && t.ParticipantID != "" {
+			for _, dr := range session.Draws {
+				if dr.ParticipantID == t.ParticipantID && dr.Outcome == core.OutcomePending {
+					draw = dr
+				}
+			}
+		}
+		if draw != nil {
+			d.DeliverTune(draw, t)
+		} else {
+			if session != nil {
+				t.Theme = session.Theme
+			}
+			d.Tunes = append(d.Tunes, t)
+		}
+		res, _ := replyCreated(newTuneJSON(d, len(d.Tunes)-1))
+		return "add " + first(t.Name, t.Link), res, nil
+	})
+	respond(w, res, err)
+}
+
+// fetchInfo fills in what the title provider knows about the tune's video,
+// keeping a name the request gave.
+func (s *Server) fetchInfo(r *http.Request, t *core.Tune) error {
+	if ip, ok := s.yt.(playlist.InfoProvider); ok {
+		info, err := ip.FetchInfo(r.Context(), t.ID)
+		if err != nil {
+			return err
+		}
+		t.Name, t.Channel, t.Seconds = first(t.Name, info.Title), info.Channel, int(info.Duration.Seconds())
+		return nil
+	}
+	if t.Name != "" {
+		return nil
+	}
+	title, err := s.yt.FetchTitle(r.Context(), t.ID)
+	if err != nil {
+		return err
+	}
+	t.Name = title
+	return nil
+}
+
+func first(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// vote rates a tune, given by video ID or number in the list. Zero stars
+// withdraws the vote; leaving stars out is a mistake, not a withdrawal.
+func (s *Server) vote(r *http.Request, d *core.Data) (string, reply, error) {
+	var body struct {
+		Participant string `json:"participant"`
+		Stars       *int   `json:"stars"`
+	}
+	if err := decode(r, &body); err != nil {
+		return "", reply{}, err
+	}
+	if body.Stars == nil {
+		return "", reply{}, errorf(http.StatusBadRequest, "stars is missing, give 1-5 or 0 to withdraw the vote")
+	}
+	stars := *body.Stars
+	p, err := findParticipant(d, body.Participant)
+	if err != nil {
+		return "", reply{}, err
+	}
+	ref := r.PathValue("ref")
+	i, err := d.FindTune(ref)
+	if err != nil {
+		return "", reply{}, fmt.Errorf("%w: %s", err, ref)
+	}
+	if err := d.Vote(i, p.ID, stars); err != nil {
+		return "", reply{}, err
+	}
+	res, _ := replyOK(newTuneJSON(d, i))
+	return fmt.Sprintf("vote %s %s %d", p.Name, ref, stars), res, nil
+}
+
+// playlist answers with a link that plays the YouTube tunes, all of them or
+// those of a theme.
+func (s *Server) playlist(r *http.Request, d *core.Data) (reply, error) {
+	var ids []string
+	for _, t := range d.TunesWithTheme(r.URL.Query().Get("theme")) {
+		if t.ID != "" {
+			ids = append(ids, t.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return reply{}, errorf(http.StatusNotFound, "no YouTube tunes to make a playlist of")
+	}
+	return replyOK(map[string]any{"url": playlist.WatchURL(ids), "tunes": len(ids)})
+}
+
+// participantStatsJSON is a row of the leaderboard.
+type participantStatsJSON struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Archived  bool        `json:"archived,omitempty"`
+	Counts    core.Counts `json:"counts"`
+	Monthly   []int       `json:"monthly"`
+	Expected  float64     `json:"expected_draws"`
+	FairRatio float64     `json:"fair_ratio"`
+	LastTune  *time.Time  `json:"last_tune,omitempty"`
+}
+
+// stats answers with the numbers of the statistics screen.
+func (s *Server) stats(r *http.Request, d *core.Data) (reply, error) {
+	db := stats.NewDashboard(d, s.now())
+	out := struct {
+		Draws        int                    `json:"draws"`
+		Tunes        int                    `json:"tunes"`
+		MonthStart   string                 `json:"month_start"`
+		Participants []participantStatsJSON `json:"participants"`
+		Platforms    map[string]int         `json:"platforms"`
+	}{
+		Draws:        db.Draws,
+		Tunes:        len(d.Tunes),
+		MonthStart:   db.MonthStart.Format("2006-01"),
+		Participants: []participantStatsJSON{},
+		Platforms:    make(map[string]int),
+	}
+	for _, p := range db.Participants {
+		row := participantStatsJSON{
+			ID: p.ID, Name: p.Name, Archived: p.Archived, Counts: p.Counts,
+			Monthly: p.Monthly, Expected: p.Expected, FairRatio: p.FairRatio(),
+		}
+		if !p.LastTune.IsZero() {
+			row.LastTune = &p.LastTune
+		}
+		out.Participants = append(out.Participants, row)
+	}
+	for _, nc := range db.Platforms {
+		out.Platforms[nc.Name] = nc.Count
+	}
+	return replyOK(out)
+}
@@ -0,0 +1,218 @@
+// Package server offers Tunesday as a JSON REST API, so that the whole team
+// can draw providers, add tunes and vote from wherever they are, not only on
+// the laptop running the app. The API is described in openapi.json, served
+// at /api/openapi.json.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"tunesday/internal/core"
+	"tunesday/internal/playlist"
+	"tunesday/internal/storage"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// Server answers the API requests on top of a store. Changes are made one at
+// a time, each loading the data, changing it and saving it before the next
+// one starts, so concurrent requests never overwrite each other's changes.
+type Server struct {
+	store storage.Store
+	yt    playlist.TitleProvider
+	token string
+	now   func() time.Time
+
+	// mu is held for writing during a change and for reading while the
+	// data is read, since stores may hand out data they share
+	mu  sync.RWMutex
+	mux *http.ServeMux
+}
+
+func New(store storage.Store, yt playlist.TitleProvider) *Server {
+	s := &Server{store: store, yt: yt, now: time.Now, mux: http.NewServeMux()}
+	s.routes()
+	return s
+}
+
+// SetToken makes every request except the one for the API description carry
+// "Authorization: Bearer <token>".
+func (s *Server) SetToken(token string) { s.token = token }
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.URL.Path != "/api/openapi.json" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="tunesday"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or wrong token"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	s.mux.HandleFunc("GET /api/participants", s.read(s.listParticipants))
+	s.mux.HandleFunc("POST /api/participants", s.write(s.addParticipant))
+	s.mux.HandleFunc("GET /api/participants/{id}", s.read(s.getParticipant))
+	s.mux.HandleFunc("PATCH /api/participants/{id}", s.write(s.changeParticipant))
+	s.mux.HandleFunc("DELETE /api/participants/{id}", s.write(s.removeParticipant))
+	s.mux.HandleFunc("GET /api/session", s.read(s.getSession))
+	s.mux.HandleFunc("POST /api/draws", s.write(s.draw))
+	s.mux.HandleFunc("GET /api/tunes", s.read(s.listTunes))
+	s.mux.HandleFunc("POST /api/tunes", s.addTune)
+	s.mux.HandleFunc("POST /api/tunes/{ref}/votes", s.write(s.vote))
+	s.mux.HandleFunc("GET /api/playlist", s.read(s.playlist))
+	s.mux.HandleFunc("GET /api/stats", s.read(s.stats))
+}
+
+// apiError is an error with the HTTP status it answers a request with.
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+func (e *apiError) Unwrap() error { return e.err }
+
+func errorf(status int, format string, args ...any) error {
+	return &apiError{status, fmt.Errorf(format, args...)}
+}
+
+// statusOf picks the status for err: its own when it has one, else the one
+// that fits the core error it is.
+func statusOf(err error) int {
+	var ae *apiError
+	switch {
+	case errors.As(err, &ae):
+		return ae.status
+	case errors.Is(err, core.ErrParticipantNotFound), errors.Is(err, core.ErrTuneNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, core.ErrParticipantExists):
+		return http.StatusConflict
+	case errors.Is(err, core.ErrEmptyName), errors.Is(err, core.ErrInvalidRating):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// reply is the result of a handler: the status and what goes in the body,
+// nothing for 204.
+type reply struct {
+	status int
+	body   any
+}
+
+func replyOK(body any) (reply, error)      { return reply{http.StatusOK, body}, nil }
+func replyCreated(body any) (reply, error) { return reply{http.StatusCreated, body}, nil }
+
+// read serves a request that only looks at the data.
+func (s *Server) read(h func(r *http.Request, d *core.Data) (reply, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		d, err := s.store.Load(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		res, err := h(r, d)
+		respond(w, res, err)
+	}
+}
+
+// write serves a request that changes the data; see change.
+func (s *Server) write(h func(r *http.Request, d *core.Data) (string, reply, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := s.change(r.Context(), func(d *core.Data) (string, reply, error) { return h(r, d) })
+		respond(w, res, err)
+	}
+}
+
+// change applies f to the data and saves it, recording the change in the
+// undo history when the store keeps one, so that "tunesday undo" takes it
+// back. f names the change; when it fails, nothing is saved.
+func (s *Server) change(ctx context.Context, f func(d *core.Data) (string, reply, error)) (reply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, err := s.store.Load(ctx)
+	if err != nil {
+		return reply{}, err
+	}
+	before := d.Clone()
+	action, res, err := f(d)
+	if err != nil {
+		return reply{}, err
+	}
+	hs, hasHistory := s.store.(storage.HistoryStore)
+	var h *core.History
+	if hasHistory {
+		if h, err = hs.LoadHistory(ctx); err != nil {
+			return reply{}, err
+		}
+		_ = h.Record(action, before, s.now())
+	}
+	if err := s.store.Save(ctx, d); err != nil {
+		return reply{}, err
+	}
+	if hasHistory {
+		if err := hs.SaveHistory(ctx, h); err != nil {
+			return reply{}, err
+		}
+	}
+	return res, nil
+}
+
+func respond(w http.ResponseWriter, res reply, err error) {
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	if res.body == nil {
+		w.WriteHeader(res.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(res.body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// maxBody is the most a request body may hold.
+const maxBody = 1 << 20
+
+// decode reads the JSON body of r into v, which may not have fields v
+// doesn't know.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errorf(http.StatusBadRequest, "the request has no body")
+		}
+		return errorf(http.StatusBadRequest, "the request body: %v", err)
+	}
+	return nil
+}
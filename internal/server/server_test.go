@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"tunesday/internal/storage"
+)
+
+type fakeYouTube struct{}
+
+func (fakeYouTube) NormalizeYouTubeID(raw string) (string, bool) {
+	if i := strings.Index(raw, "youtu.be/"); i >= 0 {
+		return raw[i+len("youtu.be/"):], true
+	}
+	return "", false
+}
+
+func (fakeYouTube) FetchTitle(ctx context.Context, id string) (string, error) {
+	if id == "broken" {
+		return "", fmt.Errorf("video unavailable")
+	}
+	return "Title of " + id, nil
+}
+
+// call sends a request to the API and decodes the answer into out, unless
+// out is nil. It fails the test when the status isn't want.
+func call(t *testing.T, srv *httptest.Server, method, path, body string, want int, out any) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, path, resp.StatusCode, want, b)
+	}
+	if out != nil {
+		if err := json.Unmarshal(b, out); err != nil {
+			t.Fatalf("%s %s: %v\n%s", method, path, err, b)
+		}
+	}
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *storage.FileStore) {
+	store := storage.NewFileStore(filepath.Join(t.TempDir(), "tunesday.json"))
+	srv := httptest.NewServer(New(store, fakeYouTube{}))
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func TestAPI(t *testing.T) {
+	srv, store := newTestServer(t)
+
+	var ann, bob participantJSON
+	call(t, srv, "POST", "/api/participants", `{"name": "Ann"}`, http.StatusCreated, &ann)
+	call(t, srv, "POST", "/api/participants", `{"name": "Bob", "handle": "bobby"}`, http.StatusCreated, &bob)
+	call(t, srv, "POST", "/api/participants", `{"name": "ann"}`, http.StatusConflict, nil)
+	call(t, srv, "POST", "/api/participants", `{"name": " "}`, http.StatusBadRequest, nil)
+	call(t, srv, "POST", "/api/participants", `{"nickname": "Cy"}`, http.StatusBadRequest, nil)
+	var ps []participantJSON
+	call(t, srv, "GET", "/api/participants", "", http.StatusOK, &ps)
+	if len(ps) != 2 || ps[0].Name != "Ann" || ps[1].Handle != "bobby" {
+		t.Fatalf("participants = %+v", ps)
+	}
+	call(t, srv, "GET", "/api/participants/nobody", "", http.StatusNotFound, nil)
+
+	// Bob sits this one out, so the draw picks Ann
+	call(t, srv, "PATCH", "/api/participants/bobby", `{"disabled": true}`, http.StatusOK, nil)
+	var session sessionJSON
+	call(t, srv, "POST", "/api/draws", "", http.StatusCreated, &session)
+	if len(session.Draws) != 1 || session.Draws[0].Name != "Ann" || session.Draws[0].Outcome != "pending" {
+		t.Fatalf("session after the draw = %+v", session)
+	}
+	call(t, srv, "POST", "/api/draws", "", http.StatusConflict, nil)
+
+	var tune tuneJSON
+	call(t, srv, "POST", "/api/tunes", `{"link": "https://youtu.be/abc&si=track", "participant": "Ann"}`, http.StatusCreated, &tune)
+	if tune.Number != 1 || tune.ID != "abc" || tune.Name != "Title of abc" || tune.By != "Ann" || tune.SessionID == "" {
+		t.Errorf("tune = %+v", tune)
+	}
+	call(t, srv, "GET", "/api/session", "", http.StatusOK, &session)
+	if session.Draws[0].Outcome != "delivered" {
+		t.Errorf("the draw is %s after Ann's tune", session.Draws[0].Outcome)
+	}
+	call(t, srv, "POST", "/api/tunes", `{"link": "https://example.com/song", "name": "Song", "participant": "`+bob.ID+`"}`, http.StatusCreated, nil)
+	call(t, srv, "POST", "/api/tunes", `{"link": "https://youtu.be/broken"}`, http.StatusBadGateway, nil)
+	call(t, srv, "POST", "/api/tunes", `{"link": "https://youtu.be/x", "participant": "Cy"}`, http.StatusNotFound, nil)
+
+	call(t, srv, "POST", "/api/tunes/abc/votes", `{"participant": "Bob", "stars": 4}`, http.StatusOK, &tune)
+	if tune.Score != 4 {
+		t.Errorf("score = %v after one vote of 4", tune.Score)
+	}
+	call(t, srv, "POST", "/api/tunes/2/votes", `{"participant": "Bob", "stars": 6}`, http.StatusBadRequest, nil)
+	// a vote without stars is a mistake and keeps the vote
+	call(t, srv, "POST", "/api/tunes/abc/votes", `{"participant": "Bob"}`, http.StatusBadRequest, nil)
+	call(t, srv, "POST", "/api/tunes/abc/votes", `{"participant": "Bob", "rating": 4}`, http.StatusBadRequest, nil)
+	call(t, srv, "POST", "/api/tunes/zzz/votes", `{"participant": "Bob", "stars": 3}`, http.StatusNotFound, nil)
+
+	var tunes []tuneJSON
+	call(t, srv, "GET", "/api/tunes?sort=title", "", http.StatusOK, &tunes)
+	if len(tunes) != 2 || tunes[0].Name != "Song" || tunes[0].Number != 2 {
+		t.Errorf("tunes by title = %+v", tunes)
+	}
+	call(t, srv, "GET", "/api/tunes?participant=Ann&q=abc", "", http.StatusOK, &tunes)
+	if len(tunes) != 1 || tunes[0].ID != "abc" || tunes[0].Score != 4 {
+		t.Errorf("Ann's tunes = %+v", tunes)
+	}
+	call(t, srv, "GET", "/api/tunes?sort=loudest", "", http.StatusBadRequest, nil)
+
+	var pl struct {
+		URL   string `json:"url"`
+		Tunes int    `json:"tunes"`
+	}
+	call(t, srv, "GET", "/api/playlist", "", http.StatusOK, &pl)
+	if pl.URL != "https://www.youtube.com/watch_videos?video_ids=abc" || pl.Tunes != 1 {
+		t.Errorf("playlist = %+v", pl)
+	}
+	call(t, srv, "GET", "/api/playlist?theme=none", "", http.StatusNotFound, nil)
+
+	var st struct {
+		Tunes        int                    `json:"tunes"`
+		Draws        int                    `json:"draws"`
+		Participants []participantStatsJSON `json:"participants"`
+	}
+	call(t, srv, "GET", "/api/stats", "", http.StatusOK, &st)
+	if st.Tunes != 2 || st.Draws != 1 || len(st.Participants) != 2 || st.Participants[0].Counts.Delivered != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+
+	call(t, srv, "DELETE", "/api/participants/"+bob.ID, "", http.StatusNoContent, nil)
+	call(t, srv, "GET", "/api/tunes", "", http.StatusOK, &tunes)
+	if len(tunes) != 1 {
+		t.Errorf("Bob's tune is still there: %+v", tunes)
+	}
+
+	// every change can be undone
+	h, err := store.LoadHistory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := h.LastUndo(); len(h.Done) != 8 || last.Action != "remove Bob" {
+		t.Errorf("history has %d changes, the last %q", len(h.Done), last.Action)
+	}
+}
+
+func TestConcurrentChanges(t *testing.T) {
+	srv, store := newTestServer(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := srv.Client().Post(srv.URL+"/api/participants", "application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "P%d"}`, i)))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+	d, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Participants) != 20 {
+		t.Errorf("%d participants saved, want all 20", len(d.Participants))
+	}
+}
+
+func TestToken(t *testing.T) {
+	store := storage.NewFileStore(filepath.Join(t.TempDir(), "tunesday.json"))
+	api := New(store, fakeYouTube{})
+	api.SetToken("s3cret")
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	call(t, srv, "GET", "/api/participants", "", http.StatusUnauthorized, nil)
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	call(t, srv, "GET", "/api/openapi.json", "", http.StatusOK, &spec)
+	if spec.OpenAPI == "" || spec.Paths["/tunes"] == nil {
+		t.Errorf("openapi.json = %+v", spec)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/api/participants", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("with the token: status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,137 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+// Months is the number of months covered by the tunes-over-time sparklines.
+const Months = 12
+
+// ParticipantStats is one participant's row on the dashboard.
+type ParticipantStats struct {
+	ID        string
+	Name      string
+	Disabled  bool
+	Archived  bool        // left the team
+	Counts    core.Counts // Drawn leaves out legacy draws
+	Monthly   []int       // tunes per month over the last Months months, oldest first
+	Expected  float64     // draws they would have had if every draw were fair
+	LastTune  time.Time   // zero when they never brought one
+	DaysSince int         // days since LastTune, -1 when never
+}
+
+// FairRatio compares actual draws with the fair expectation; 1 is exactly fair.
+func (p ParticipantStats) FairRatio() float64 {
+	if p.Expected == 0 {
+		return 0
+	}
+	return float64(p.Counts.Drawn) / p.Expected
+}
+
+// Dashboard holds the numbers shown on the statistics screen.
+type Dashboard struct {
+	Participants []ParticipantStats // leaderboard order: most tunes first
+	Platforms    []NameCount
+	Draws        int       // draws recorded in sessions
+	MonthStart   time.Time // first month of the sparklines
+}
+
+// NewDashboard computes the dashboard at now.
+//
+// The fair expectation splits every random draw evenly between the
+// participants who had joined by the day of the draw and hadn't left yet.
+// Legacy draws without a date are left out of both sides.
+func NewDashboard(d *core.Data, now time.Time) *Dashboard {
+	db := &Dashboard{}
+	y, m, _ := now.Date()
+	db.MonthStart = time.Date(y, m, 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1-Months, 0)
+
+	counts := d.Counts()
+	rows := make(map[string]*ParticipantStats, len(d.Participants))
+	for _, p := range d.SortedParticipants() {
+		c := counts[p.ID]
+		c.Drawn -= p.LegacyDraws
+		rows[p.ID] = &ParticipantStats{ID: p.ID, Name: p.Name, Disabled: p.Disabled, Archived: p.Archived, Counts: c, Monthly: make([]int, Months), DaysSince: -1}
+	}
+
+	platforms := make(map[string]int)
+	for _, t := range d.Tunes {
+		platforms[t.Platform()]++
+		r := rows[t.ParticipantID]
+		if r == nil || t.AddedAt.IsZero() {
+			continue
+		}
+		if t.AddedAt.After(r.LastTune) {
+			r.LastTune = t.AddedAt
+		}
+		at := t.AddedAt.In(now.Location())
+		idx := (at.Year()-db.MonthStart.Year())*12 + int(at.Month()) - int(db.MonthStart.Month())
+		if idx >= 0 && idx < Months {
+			r.Monthly[idx]++
+		}
+	}
+	db.Platforms = ranking(platforms, 0)
+
+	for _, s := range d.Sessions {
+		var eligible []*ParticipantStats
+		for _, p := range d.Participants {
+			if p.MemberOn(s.Date) {
+				eligible = append(eligible, rows[p.ID])
+			}
+		}
+		if len(eligible) == 0 {
+			for _, r := range rows {
+				eligible = append(eligible, r)
+			}
+		}
+		for _, dr := range s.Draws {
+			if dr.Volunteer {
+				continue
+			}
+			db.Draws++
+			for _, r := range eligible {
+				r.Expected += 1 / float64(len(eligible))
+			}
+		}
+	}
+
+	for _, r := range rows {
+		if !r.LastTune.IsZero() {
+			r.DaysSince = int(now.Sub(r.LastTune).Hours() / 24)
+		}
+		db.Participants = append(db.Participants, *r)
+	}
+	sort.SliceStable(db.Participants, func(i, j int) bool {
+		a, b := db.Participants[i], db.Participants[j]
+		if a.Counts.Delivered != b.Counts.Delivered {
+			return a.Counts.Delivered > b.Counts.Delivered
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return db
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders counts as a row of block characters scaled to max.
+// Zero is shown as a space so that empty months stand out.
+func Sparkline(counts []int, max int) string {
+	var b strings.Builder
+	for _, n := range counts {
+		switch {
+		case n <= 0 || max <= 0:
+			b.WriteRune(' ')
+		default:
+			i := (n*len(sparks) - 1) / max
+			if i >= len(sparks) {
+				i = len(sparks) - 1
+			}
+			b.WriteRune(sparks[i])
+		}
+	}
+	return b.String()
+}
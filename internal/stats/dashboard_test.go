@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+func TestNewDashboard(t *testing.T) {
+	d := sampleData(t)
+	ann := d.ParticipantByName("Ann")
+	bob := d.ParticipantByName("Bob")
+	ann.JoinedAt = time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	bob.JoinedAt = time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	d.Tunes = append(d.Tunes, core.Tune{Name: "Cloud", Link: "https://soundcloud.com/x/y", ParticipantID: bob.ID,
+		AddedAt: time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)})
+
+	db := NewDashboard(d, time.Date(2026, 2, 13, 10, 0, 0, 0, time.UTC))
+
+	if db.Draws != 3 {
+		t.Fatalf("draws = %d, want 3", db.Draws)
+	}
+	if !db.MonthStart.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("month start = %v", db.MonthStart)
+	}
+	if len(db.Participants) != 2 || db.Participants[0].Name != "Bob" {
+		t.Fatalf("leaderboard = %+v", db.Participants)
+	}
+	b, a := db.Participants[0], db.Participants[1]
+	// Bob only joined before the last of the three sessions.
+	if a.Expected != 2.5 || b.Expected != 0.5 {
+		t.Fatalf("expected draws: ann=%v bob=%v", a.Expected, b.Expected)
+	}
+	if b.FairRatio() != 2 {
+		t.Fatalf("bob fair ratio = %v, want 2", b.FairRatio())
+	}
+	if a.Monthly[10] != 2 || b.Monthly[9] != 1 || b.Monthly[10] != 1 || b.Monthly[11] != 1 {
+		t.Fatalf("monthly: ann=%v bob=%v", a.Monthly, b.Monthly)
+	}
+	if a.DaysSince != 31 || b.DaysSince != 10 {
+		t.Fatalf("days since: ann=%d bob=%d", a.DaysSince, b.DaysSince)
+	}
+	if len(db.Platforms) != 2 || db.Platforms[0] != (NameCount{"other", 4}) || db.Platforms[1] != (NameCount{"SoundCloud", 1}) {
+		t.Fatalf("platforms = %+v", db.Platforms)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 1, 4, 8}, 8); got != " ▁▄█" {
+		t.Fatalf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]int{3, 0}, 0); got != "  " {
+		t.Fatalf("Sparkline with zero max = %q", got)
+	}
+}
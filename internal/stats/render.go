@@ -0,0 +1,291 @@
+package stats
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// ANSIStyles colours the report for a terminal, usually with the styles of
+// the skin in use. A nil style leaves the text as it is.
+type ANSIStyles struct {
+	Title func(string) string // the heading and the section titles
+	Bar   func(string) string // the bars of the charts
+}
+
+func apply(style func(string) string, s string) string {
+	if style == nil {
+		return s
+	}
+	return style(s)
+}
+
+// Bar returns a bar of block characters scaled so that max fills width.
+func Bar(n, max, width int) string {
+	if max <= 0 || n <= 0 {
+		return ""
+	}
+	cells := n * width / max
+	if cells == 0 {
+		cells = 1
+	}
+	return strings.Repeat("█", cells)
+}
+
+// clock formats a length in seconds as m:ss or h:mm:ss.
+func clock(seconds int) string {
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+func maxCount(rows []NameCount) int {
+	max := 0
+	for _, r := range rows {
+		if r.Count > max {
+			max = r.Count
+		}
+	}
+	return max
+}
+
+func nameWidth(rows []NameCount) int {
+	w := 0
+	for _, r := range rows {
+		if n := len([]rune(r.Name)); n > w {
+			w = n
+		}
+	}
+	return w
+}
+
+func (w *Wrapped) streakText() string {
+	s := w.TeamStreak
+	if s.Weeks == 0 {
+		return "no tunes yet"
+	}
+	return fmt.Sprintf("%d weeks in a row (weeks of %s to %s)", s.Weeks, s.From.Format("Jan 2"), s.To.Format("Jan 2"))
+}
+
+func scoreText(t *TuneRef) string {
+	votes := "votes"
+	if len(t.Votes) == 1 {
+		votes = "vote"
+	}
+	return fmt.Sprintf("%.1f from %d %s", t.Score(), len(t.Votes), votes)
+}
+
+func tuneText(t *TuneRef) string {
+	return fmt.Sprintf("%s by %s", tuneTitle(t), t.By)
+}
+
+func tuneTitle(t *TuneRef) string {
+	if t.Name == "" {
+		return t.Link
+	}
+	return t.Name
+}
+
+// mdEscaper keeps names and titles from being read as Markdown, such as the
+// "|" of "Artist - Song | Official Video" ending a table cell.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "|", `\|`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func md(s string) string { return mdEscaper.Replace(s) }
+
+func mdTuneText(t *TuneRef) string {
+	return fmt.Sprintf("%s by %s", md(tuneTitle(t)), md(t.By))
+}
+
+// WriteANSI renders the report for a terminal of the given width, with bar
+// charts, in the given styles.
+func (w *Wrapped) WriteANSI(out io.Writer, width int, styles ANSIStyles) {
+	section := func(title string) {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, apply(styles.Title, title))
+	}
+	chart := func(rows []NameCount) {
+		nw := nameWidth(rows)
+		barW := width - nw - 10
+		if barW > 40 {
+			barW = 40
+		}
+		if barW < 10 {
+			barW = 10
+		}
+		max := maxCount(rows)
+		for _, r := range rows {
+			pad := strings.Repeat(" ", nw-len([]rune(r.Name)))
+			fmt.Fprintf(out, "  %s%s  %s %d\n", r.Name, pad, apply(styles.Bar, Bar(r.Count, max, barW)), r.Count)
+		}
+	}
+
+	fmt.Fprintln(out, apply(styles.Title, fmt.Sprintf("♫ Tunesday Wrapped %d ♫", w.Year)))
+	fmt.Fprintf(out, "%d tunes over %d Tunesdays\n", w.Tunes, w.Sessions)
+	if w.Tunes == 0 {
+		return
+	}
+
+	section("Tunes per participant")
+	chart(w.Providers)
+
+	if len(w.Channels) > 0 {
+		section("Most played channels")
+		chart(w.Channels)
+	}
+
+	section("Records")
+	if w.Longest != nil {
+		fmt.Fprintf(out, "  Longest:   %s (%s)\n", tuneText(w.Longest), clock(w.Longest.Seconds))
+		fmt.Fprintf(out, "  Shortest:  %s (%s)\n", tuneText(w.Shortest), clock(w.Shortest.Seconds))
+	}
+	if w.TopRated != nil {
+		fmt.Fprintf(out, "  Top rated: %s (%s)\n", tuneText(w.TopRated), scoreText(w.TopRated))
+	}
+	fmt.Fprintf(out, "  Streak:    %s\n", w.streakText())
+
+	if len(w.Reliable) > 0 {
+		section("Delivered every time they were drawn")
+		for _, r := range w.Reliable {
+			fmt.Fprintf(out, "  %s: %d draws in a row\n", r.Name, r.Count)
+		}
+	}
+	if len(w.FirstTimers) > 0 {
+		section("First-time providers")
+		fmt.Fprintln(out, "  "+strings.Join(w.FirstTimers, ", "))
+	}
+}
+
+// WriteMarkdown renders the report as a Markdown document.
+func (w *Wrapped) WriteMarkdown(out io.Writer) error {
+	var b strings.Builder
+	table := func(head string, rows []NameCount) {
+		max := maxCount(rows)
+		fmt.Fprintf(&b, "| %s | Tunes | |\n|---|---:|---|\n", head)
+		for _, r := range rows {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", md(r.Name), r.Count, Bar(r.Count, max, 20))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "# ♫ Tunesday Wrapped %d ♫\n\n", w.Year)
+	fmt.Fprintf(&b, "%d tunes over %d Tunesdays.\n\n", w.Tunes, w.Sessions)
+	if w.Tunes > 0 {
+		b.WriteString("## Tunes per participant\n\n")
+		table("Participant", w.Providers)
+		if len(w.Channels) > 0 {
+			b.WriteString("## Most played channels\n\n")
+			table("Channel", w.Channels)
+		}
+		b.WriteString("## Records\n\n")
+		if w.Longest != nil {
+			fmt.Fprintf(&b, "- **Longest:** %s (%s)\n", mdTuneText(w.Longest), clock(w.Longest.Seconds))
+			fmt.Fprintf(&b, "- **Shortest:** %s (%s)\n", mdTuneText(w.Shortest), clock(w.Shortest.Seconds))
+		}
+		if w.TopRated != nil {
+			fmt.Fprintf(&b, "- **Top rated:** %s (%s)\n", mdTuneText(w.TopRated), scoreText(w.TopRated))
+		}
+		fmt.Fprintf(&b, "- **Streak:** %s\n\n", w.streakText())
+		if len(w.Reliable) > 0 {
+			b.WriteString("## Delivered every time they were drawn\n\n")
+			for _, r := range w.Reliable {
+				fmt.Fprintf(&b, "- %s: %d draws in a row\n", md(r.Name), r.Count)
+			}
+			b.WriteString("\n")
+		}
+		if len(w.FirstTimers) > 0 {
+			names := make([]string, len(w.FirstTimers))
+			for i, name := range w.FirstTimers {
+				names[i] = md(name)
+			}
+			fmt.Fprintf(&b, "## First-time providers\n\n%s\n", strings.Join(names, ", "))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+var htmlReport = template.Must(template.New("wrapped").Funcs(template.FuncMap{
+	"pct": func(n, max int) int {
+		if max == 0 {
+			return 0
+		}
+		return n * 100 / max
+	},
+	"max":   maxCount,
+	"clock": clock,
+	"tune":  tuneText,
+	"score": scoreText,
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Tunesday Wrapped {{.Year}}</title>
+<style>
+body { font-family: system-ui, sans-serif; background: #111; color: #eee; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; }
+h1 { color: #1db954; }
+h2 { border-bottom: 1px solid #333; padding-bottom: .25rem; }
+.row { display: flex; align-items: center; margin: .25rem 0; }
+.name { width: 12rem; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar { background: #1db954; height: 1rem; margin-right: .5rem; }
+</style>
+</head>
+<body>
+<h1>♫ Tunesday Wrapped {{.Year}} ♫</h1>
+<p>{{.Tunes}} tunes over {{.Sessions}} Tunesdays.</p>
+{{- if .Tunes}}
+<h2>Tunes per participant</h2>
+{{- $max := max .Providers}}
+{{- range .Providers}}
+<div class="row"><span class="name">{{.Name}}</span><span class="bar" style="width: {{pct .Count $max}}%"></span>{{.Count}}</div>
+{{- end}}
+{{- if .Channels}}
+<h2>Most played channels</h2>
+{{- $max := max .Channels}}
+{{- range .Channels}}
+<div class="row"><span class="name">{{.Name}}</span><span class="bar" style="width: {{pct .Count $max}}%"></span>{{.Count}}</div>
+{{- end}}
+{{- end}}
+<h2>Records</h2>
+<ul>
+{{- with .Longest}}
+<li><strong>Longest:</strong> {{tune .}} ({{clock .Seconds}})</li>
+{{- end}}
+{{- with .Shortest}}
+<li><strong>Shortest:</strong> {{tune .}} ({{clock .Seconds}})</li>
+{{- end}}
+{{- with .TopRated}}
+<li><strong>Top rated:</strong> {{tune .}} ({{score .}})</li>
+{{- end}}
+<li><strong>Streak:</strong> {{.StreakText}}</li>
+</ul>
+{{- if .Reliable}}
+<h2>Delivered every time they were drawn</h2>
+<ul>
+{{- range .Reliable}}
+<li>{{.Name}}: {{.Count}} draws in a row</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .FirstTimers}}
+<h2>First-time providers</h2>
+<p>{{join .FirstTimers ", "}}</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML renders the report as a standalone HTML page.
+func (w *Wrapped) WriteHTML(out io.Writer) error {
+	return htmlReport.Execute(out, struct {
+		*Wrapped
+		StreakText string
+	}{w, w.streakText()})
+}
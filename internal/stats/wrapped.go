@@ -0,0 +1,184 @@
+// Package stats computes statistics and reports from Tunesday data.
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+// NameCount is one row of a ranking.
+type NameCount struct {
+	Name  string
+	Count int
+}
+
+// Streak is a run of consecutive weeks.
+type Streak struct {
+	Weeks    int
+	From, To time.Time
+}
+
+// TuneRef is a tune together with the name of whoever brought it.
+type TuneRef struct {
+	core.Tune
+	By string
+}
+
+// Wrapped is the yearly summary of a Tunesday team.
+type Wrapped struct {
+	Year        int
+	Tunes       int
+	Sessions    int
+	Providers   []NameCount // tunes per participant, most first
+	Channels    []NameCount // most played channels, at most five
+	Longest     *TuneRef
+	Shortest    *TuneRef
+	TopRated    *TuneRef
+	TeamStreak  Streak      // consecutive weeks with at least one tune
+	Reliable    []NameCount // longest run of delivered draws per participant
+	FirstTimers []string    // participants whose first tune ever was this year
+}
+
+func providerName(d *core.Data, t core.Tune) string {
+	if name := d.ParticipantName(t.ParticipantID); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// NewWrapped summarizes the given calendar year.
+func NewWrapped(d *core.Data, year int) *Wrapped {
+	w := &Wrapped{Year: year}
+
+	inYear := func(t time.Time) bool { return !t.IsZero() && t.Year() == year }
+
+	perProvider := make(map[string]int)
+	perChannel := make(map[string]int)
+	channelName := make(map[string]string)
+	weeks := make(map[time.Time]bool)
+	firstTune := make(map[string]time.Time)
+
+	for _, t := range d.Tunes {
+		if t.ParticipantID != "" && !t.AddedAt.IsZero() {
+			if first, ok := firstTune[t.ParticipantID]; !ok || t.AddedAt.Before(first) {
+				firstTune[t.ParticipantID] = t.AddedAt
+			}
+		}
+		if !inYear(t.AddedAt) {
+			continue
+		}
+		w.Tunes++
+		perProvider[providerName(d, t)]++
+		if t.Channel != "" {
+			key := strings.ToLower(t.Channel)
+			perChannel[key]++
+			channelName[key] = t.Channel
+		}
+		weeks[weekOf(t.AddedAt)] = true
+		ref := &TuneRef{Tune: t, By: providerName(d, t)}
+		if t.Seconds > 0 {
+			if w.Longest == nil || t.Seconds > w.Longest.Seconds {
+				w.Longest = ref
+			}
+			if w.Shortest == nil || t.Seconds < w.Shortest.Seconds {
+				w.Shortest = ref
+			}
+		}
+		if len(t.Votes) > 0 && (w.TopRated == nil || t.Score() > w.TopRated.Score()) {
+			w.TopRated = ref
+		}
+	}
+
+	w.Providers = ranking(perProvider, 0)
+	named := make(map[string]int, len(perChannel))
+	for key, n := range perChannel {
+		named[channelName[key]] = n
+	}
+	w.Channels = ranking(named, 5)
+	w.TeamStreak = longestStreak(weeks)
+
+	for id, first := range firstTune {
+		// tunes can point at a participant who isn't there any more
+		if p := d.Participant(id); p != nil && first.Year() == year {
+			w.FirstTimers = append(w.FirstTimers, p.Name)
+		}
+	}
+	sort.Strings(w.FirstTimers)
+
+	runs := make(map[string]int)
+	best := make(map[string]int)
+	for _, s := range d.Sessions {
+		if !inYear(s.Date) {
+			continue
+		}
+		w.Sessions++
+		for _, dr := range s.Draws {
+			name := d.ParticipantName(dr.ParticipantID)
+			switch dr.Outcome {
+			case core.OutcomeDelivered:
+				runs[name]++
+				if runs[name] > best[name] {
+					best[name] = runs[name]
+				}
+			case core.OutcomeSkipped, core.OutcomePassed, core.OutcomeSwapped:
+				runs[name] = 0
+			}
+		}
+	}
+	w.Reliable = ranking(best, 3)
+	return w
+}
+
+// ranking sorts counts descending, ties by name, keeping at most limit rows
+// (all when limit is 0).
+func ranking(m map[string]int, limit int) []NameCount {
+	out := make([]NameCount, 0, len(m))
+	for name, n := range m {
+		if name != "" && n > 0 {
+			out = append(out, NameCount{Name: name, Count: n})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// weekOf returns the Monday starting the week of t.
+func weekOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func longestStreak(weeks map[time.Time]bool) Streak {
+	var all []time.Time
+	for wk := range weeks {
+		all = append(all, wk)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Before(all[j]) })
+
+	var best, cur Streak
+	for i, wk := range all {
+		if i > 0 && wk.Equal(all[i-1].AddDate(0, 0, 7)) {
+			cur.Weeks++
+			cur.To = wk
+		} else {
+			cur = Streak{Weeks: 1, From: wk, To: wk}
+		}
+		if cur.Weeks > best.Weeks {
+			best = cur
+		}
+	}
+	return best
+}
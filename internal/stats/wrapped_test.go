@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+func sampleData(t *testing.T) *core.Data {
+	t.Helper()
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	day := func(y int, m time.Month, dd int) time.Time { return time.Date(y, m, dd, 10, 0, 0, 0, time.UTC) }
+
+	d.Tunes = []core.Tune{
+		{Name: "Old One", ID: "o", ParticipantID: bob.ID, AddedAt: day(2025, 12, 2), Seconds: 200},
+		{Name: "Long Song", ID: "a", ParticipantID: ann.ID, Channel: "Band", AddedAt: day(2026, 1, 6), Seconds: 600},
+		{Name: "Short Song", ID: "b", ParticipantID: ann.ID, Channel: "band", AddedAt: day(2026, 1, 13), Seconds: 90,
+			Votes: map[string]int{bob.ID: 5}},
+		{Name: "Middle", ID: "c", ParticipantID: bob.ID, Channel: "Other", AddedAt: day(2026, 1, 27), Seconds: 240,
+			Votes: map[string]int{ann.ID: 3}},
+	}
+	for _, tu := range d.Tunes[1:] {
+		s := d.SessionOn(tu.AddedAt)
+		s.AddDraw(tu.ParticipantID, tu.AddedAt).Outcome = core.OutcomeDelivered
+	}
+	return d
+}
+
+func TestNewWrappedSkipsUnknownFirstTimers(t *testing.T) {
+	d := sampleData(t)
+	d.Tunes = append(d.Tunes, core.Tune{Name: "Orphan", ID: "x", ParticipantID: "gone",
+		AddedAt: time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)})
+	if w := NewWrapped(d, 2026); len(w.FirstTimers) != 1 || w.FirstTimers[0] != "Ann" {
+		t.Fatalf("first timers = %q, want [Ann]", w.FirstTimers)
+	}
+}
+
+func TestNewWrapped(t *testing.T) {
+	w := NewWrapped(sampleData(t), 2026)
+
+	if w.Tunes != 3 || w.Sessions != 3 {
+		t.Fatalf("tunes=%d sessions=%d, want 3 and 3", w.Tunes, w.Sessions)
+	}
+	if len(w.Providers) != 2 || w.Providers[0] != (NameCount{"Ann", 2}) {
+		t.Fatalf("providers = %+v", w.Providers)
+	}
+	if len(w.Channels) != 2 || w.Channels[0].Count != 2 {
+		t.Fatalf("channels = %+v", w.Channels)
+	}
+	if w.Longest.Name != "Long Song" || w.Shortest.Name != "Short Song" || w.TopRated.Name != "Short Song" {
+		t.Fatalf("records: longest=%s shortest=%s top=%s", w.Longest.Name, w.Shortest.Name, w.TopRated.Name)
+	}
+	if w.TopRated.By != "Ann" {
+		t.Fatalf("top rated by %q, want Ann", w.TopRated.By)
+	}
+	if w.TeamStreak.Weeks != 2 {
+		t.Fatalf("streak = %+v, want 2 weeks", w.TeamStreak)
+	}
+	if len(w.FirstTimers) != 1 || w.FirstTimers[0] != "Ann" {
+		t.Fatalf("first timers = %v, want [Ann]", w.FirstTimers)
+	}
+	if len(w.Reliable) == 0 || w.Reliable[0] != (NameCount{"Ann", 2}) {
+		t.Fatalf("reliable = %+v", w.Reliable)
+	}
+}
+
+func TestWrappedRenderers(t *testing.T) {
+	w := NewWrapped(sampleData(t), 2026)
+
+	var ansi bytes.Buffer
+	w.WriteANSI(&ansi, 60, ANSIStyles{})
+	if strings.Contains(ansi.String(), "\x1b[") {
+		t.Fatalf("colourless output contains escape codes")
+	}
+	var styled bytes.Buffer
+	w.WriteANSI(&styled, 60, ANSIStyles{Title: strings.ToUpper, Bar: func(s string) string { return "<" + s + ">" }})
+	if !strings.Contains(styled.String(), "TUNESDAY WRAPPED 2026") || !strings.Contains(styled.String(), "<████") {
+		t.Errorf("styles not applied:\n%s", styled.String())
+	}
+	for _, want := range []string{"Tunesday Wrapped 2026", "Ann", "████", "Long Song by Ann (10:00)"} {
+		if !strings.Contains(ansi.String(), want) {
+			t.Errorf("ANSI report lacks %q:\n%s", want, ansi.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := w.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "| Ann | 2 |") {
+		t.Errorf("Markdown report lacks participant table:\n%s", md.String())
+	}
+
+	var html bytes.Buffer
+	if err := w.WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<title>Tunesday Wrapped 2026</title>") || !strings.Contains(html.String(), "width: 100%") {
+		t.Errorf("HTML report incomplete:\n%s", html.String())
+	}
+}
+
+func TestWriteMarkdownEscapes(t *testing.T) {
+	d := sampleData(t)
+	d.Tunes[1].Name = "Artist - Song | Official Video"
+	for i := range d.Tunes {
+		d.Tunes[i].Channel = "Band | *Live*"
+	}
+	var b bytes.Buffer
+	if err := NewWrapped(d, 2026).WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "|") && strings.Count(strings.ReplaceAll(line, `\|`, ""), "|") != 4 {
+			t.Errorf("row with the wrong number of cells: %s", line)
+		}
+	}
+	for _, want := range []string{`| Band \| \*Live\* | 3 |`, `Artist - Song \| Official Video by Ann`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "runtime"
+)
+
+// fileSystem is what saving needs from the operating system. Tests swap it
+// to make single steps fail.
+type fileSystem interface {
+    CreateTemp(dir, pattern string) (file, error)
+    Open(name string) (file, error)
+    Stat(name string) (fs.FileInfo, error)
+    EvalSymlinks(path string) (string, error)
+    Chmod(name string, mode fs.FileMode) error
+    Chown(name string, uid, gid int) error
+    Rename(oldpath, newpath string) error
+    Remove(name string) error
+}
+
+// file is an open file as far as saving is concerned.
+type file interface {
+    Name() string
+    Write(b []byte) (int, error)
+    Sync() error
+    Close() error
+}
+
+// osFS is the real file system.
+type osFS struct{}
+
+func (osFS) CreateTemp(dir, pattern string) (file, error) { return os.CreateTemp(dir, pattern) }
+func (osFS) Open(name string) (file, error)               { return os.Open(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (osFS) EvalSymlinks(path string) (string, error)     { return filepath.EvalSymlinks(path) }
+func (osFS) Chmod(name string, mode fs.FileMode) error    { return os.Chmod(name, mode) }
+func (osFS) Chown(name string, uid, gid int) error        { return os.Chown(name, uid, gid) }
+func (osFS) Rename(oldpath, newpath string) error         { return os.Rename(oldpath, newpath) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
+
+// writeFile replaces path with b so that a crash at any point leaves either
+// the old or the new file, never a mix:
+//
+//  1. b goes to a temporary file with a unique name in the same directory,
+//     which is synced to disk,
+//  2. the temporary file gets the mode and owner of the file it replaces,
+//  3. it is renamed over the file and the directory is synced, so that the
+//     rename itself survives a crash.
+//
+// When path is a symlink, the file it points to is replaced and the link
+// stays. New files get mode perm.
+func writeFile(sys fileSystem, path string, b []byte, perm fs.FileMode) error {
+    s, err := stageFile(sys, path, b, perm)
+    if err != nil {
+        return err
+    }
+    return s.commit(sys)
+}
+
+// staged is a file written next to the one it is to replace, steps 1 and 2
+// of writeFile.
+type staged struct {
+    tmp, target string
+}
+
+// stageFile does steps 1 and 2 of writeFile. The file is either committed or
+// discarded after.
+func stageFile(sys fileSystem, path string, b []byte, perm fs.FileMode) (s staged, err error) {
+    target, err := sys.EvalSymlinks(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        target = path
+    } else if err != nil {
+        return staged{}, err
+    }
+    mode := perm
+    uid, gid, owned := -1, -1, false
+    if info, err := sys.Stat(target); err == nil {
+        mode = info.Mode().Perm()
+        uid, gid, owned = fileOwner(info)
+    } else if !errors.Is(err, fs.ErrNotExist) {
+        return staged{}, err
+    }
+
+    f, err := sys.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+    if err != nil {
+        return staged{}, err
+    }
+    s = staged{tmp: f.Name(), target: target}
+    defer func() {
+        if err != nil {
+            s.discard(sys)
+        }
+    }()
+    if _, err := f.Write(b); err != nil {
+        f.Close()
+        return s, err
+    }
+    if err := f.Sync(); err != nil {
+        f.Close()
+        return s, err
+    }
+    if err := f.Close(); err != nil {
+        return s, err
+    }
+    if err := sys.Chmod(s.tmp, mode); err != nil {
+        return s, err
+    }
+    if owned {
+        // only root may give files away; everyone else keeps the file theirs
+        if err := sys.Chown(s.tmp, uid, gid); err != nil && !errors.Is(err, fs.ErrPermission) {
+            return s, err
+        }
+    }
+    return s, nil
+}
+
+// commit does step 3 of writeFile.
+func (s staged) commit(sys fileSystem) error {
+    if err := sys.Rename(s.tmp, s.target); err != nil {
+        s.discard(sys)
+        return err
+    }
+    if err := syncDir(sys, filepath.Dir(s.target)); err != nil {
+        return fmt.Errorf("saved %s, but syncing its directory failed: %w", s.target, err)
+    }
+    return nil
+}
+
+// discard removes the temporary file.
+func (s staged) discard(sys fileSystem) { _ = sys.Remove(s.tmp) }
+
+// syncDir flushes a directory entry to disk. Windows can't sync directories
+// and doesn't need to.
+func syncDir(sys fileSystem, dir string) error {
+    if runtime.GOOS == "windows" {
+        return nil
+    }
+    d, err := sys.Open(dir)
+    if err != nil {
+        return err
+    }
+    if err := d.Sync(); err != nil {
+        d.Close()
+        return err
+    }
+    return d.Close()
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "testing"
+
+    "tunesday/internal/core"
+)
+
+// failFS is the real file system with one step failing.
+type failFS struct {
+    osFS
+    step string
+}
+
+var errInjected = errors.New("injected failure")
+
+func (f failFS) fail(step string) error {
+    if f.step == step {
+        return errInjected
+    }
+    return nil
+}
+
+func (f failFS) CreateTemp(dir, pattern string) (file, error) {
+    if err := f.fail("create"); err != nil {
+        return nil, err
+    }
+    tmp, err := f.osFS.CreateTemp(dir, pattern)
+    if err != nil {
+        return nil, err
+    }
+    return failFile{tmp, f}, nil
+}
+
+func (f failFS) Open(name string) (file, error) {
+    if err := f.fail("opendir"); err != nil {
+        return nil, err
+    }
+    d, err := f.osFS.Open(name)
+    if err != nil {
+        return nil, err
+    }
+    return failFile{d, failFS{step: strings.Replace(f.step, "syncdir", "sync", 1)}}, nil
+}
+
+func (f failFS) Chmod(name string, mode fs.FileMode) error {
+    if err := f.fail("chmod"); err != nil {
+        return err
+    }
+    return f.osFS.Chmod(name, mode)
+}
+
+func (f failFS) Chown(name string, uid, gid int) error {
+    if err := f.fail("chown"); err != nil {
+        return err
+    }
+    return f.osFS.Chown(name, uid, gid)
+}
+
+func (f failFS) Rename(oldpath, newpath string) error {
+    if err := f.fail("rename"); err != nil {
+        return err
+    }
+    return f.osFS.Rename(oldpath, newpath)
+}
+
+type failFile struct {
+    file
+    fs failFS
+}
+
+func (f failFile) Write(b []byte) (int, error) {
+    if err := f.fs.fail("write"); err != nil {
+        // a short write, like a full disk
+        n, _ := f.file.Write(b[:len(b)/2])
+        return n, err
+    }
+    return f.file.Write(b)
+}
+
+func (f failFile) Sync() error {
+    if err := f.fs.fail("sync"); err != nil {
+        return err
+    }
+    return f.file.Sync()
+}
+
+func (f failFile) Close() error {
+    err := f.file.Close()
+    if ferr := f.fs.fail("close"); ferr != nil {
+        return ferr
+    }
+    return err
+}
+
+func TestSaveFailureKeepsOldFile(t *testing.T) {
+    ctx := context.Background()
+    for _, step := range []string{"create", "write", "sync", "close", "chmod", "chown", "rename"} {
+        t.Run(step, func(t *testing.T) {
+            dir := t.TempDir()
+            path := filepath.Join(dir, "tunesday.json")
+            store := NewFileStore(path)
+            if err := store.Save(ctx, &core.Data{Tunes: []core.Tune{{Name: "Old"}}}); err != nil {
+                t.Fatal(err)
+            }
+            before, _ := os.ReadFile(path)
+
+            store.sys = failFS{step: step}
+            err := store.Save(ctx, &core.Data{Tunes: []core.Tune{{Name: "New"}}})
+            if !errors.Is(err, errInjected) {
+                t.Fatalf("Save = %v, want the injected failure", err)
+            }
+            after, _ := os.ReadFile(path)
+            if string(after) != string(before) {
+                t.Errorf("data file changed after a failed save:\n%s", after)
+            }
+            assertNoTempFiles(t, dir)
+        })
+    }
+}
+
+func TestSaveDirectorySyncFailureIsReported(t *testing.T) {
+    for _, step := range []string{"opendir", "syncdir"} {
+        t.Run(step, func(t *testing.T) {
+            dir := t.TempDir()
+            path := filepath.Join(dir, "tunesday.json")
+            store := NewFileStore(path)
+            store.sys = failFS{step: step}
+            err := store.Save(context.Background(), &core.Data{Tunes: []core.Tune{{Name: "New"}}})
+            if !errors.Is(err, errInjected) {
+                t.Fatalf("Save = %v, want the injected failure", err)
+            }
+            // the rename happened, so the new data is there
+            d, err := NewFileStore(path).loadFile(path)
+            if err != nil || len(d.Tunes) != 1 {
+                t.Fatalf("Load = %v, %v", d, err)
+            }
+            assertNoTempFiles(t, dir)
+        })
+    }
+}
+
+func TestSaveKeepsModeAndSymlink(t *testing.T) {
+    ctx := context.Background()
+    dir := t.TempDir()
+    target := filepath.Join(dir, "data", "tunesday.json")
+    if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(target, []byte("{}"), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    link := filepath.Join(dir, "tunesday.json")
+    if err := os.Symlink(target, link); err != nil {
+        t.Skip("no symlinks here:", err)
+    }
+
+    if err := NewFileStore(link).Save(ctx, &core.Data{Tunes: []core.Tune{{Name: "New"}}}); err != nil {
+        t.Fatal(err)
+    }
+    if info, err := os.Lstat(link); err != nil || info.Mode()&fs.ModeSymlink == 0 {
+        t.Fatalf("%s is no longer a symlink: %v", link, err)
+    }
+    info, err := os.Stat(target)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if info.Mode().Perm() != 0o600 {
+        t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+    }
+    d, err := NewFileStore(target).loadFile(target)
+    if err != nil || len(d.Tunes) != 1 {
+        t.Fatalf("Load = %v, %v", d, err)
+    }
+    assertNoTempFiles(t, filepath.Dir(target))
+}
+
+func TestConcurrentSavesDoNotCollide(t *testing.T) {
+    ctx := context.Background()
+    dir := t.TempDir()
+    path := filepath.Join(dir, "tunesday.json")
+    var wg sync.WaitGroup
+    errs := make(chan error, 8)
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            // separate stores, like separate processes
+            errs <- NewFileStore(path).Save(ctx, &core.Data{Tunes: make([]core.Tune, i+1)})
+        }(i)
+    }
+    wg.Wait()
+    close(errs)
+    for err := range errs {
+        if err != nil {
+            t.Error(err)
+        }
+    }
+    d, err := NewFileStore(path).loadFile(path)
+    if err != nil {
+        t.Fatalf("data file is broken after concurrent saves: %v", err)
+    }
+    if n := len(d.Tunes); n < 1 || n > 8 {
+        t.Errorf("got %d tunes, want the data of one of the saves", n)
+    }
+    assertNoTempFiles(t, dir)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+    t.Helper()
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, e := range entries {
+        if strings.HasSuffix(e.Name(), ".tmp") {
+            t.Errorf("temporary file left behind: %s", e.Name())
+        }
+    }
+}
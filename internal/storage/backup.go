@@ -0,0 +1,177 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "tunesday/internal/core"
+)
+
+// Retention says which backups of the data file are kept: the last Keep
+// versions, plus the newest version of each of the last Daily days and of
+// each of the last Weekly weeks. Zero fields take the default, and a negative
+// one keeps none, e.g. Weekly: -1 for no weekly backups.
+type Retention struct {
+    Keep   int
+    Daily  int
+    Weekly int
+}
+
+var DefaultRetention = Retention{Keep: 10, Daily: 7, Weekly: 8}
+
+func (r Retention) withDefaults() Retention {
+    if r.Keep == 0 {
+        r.Keep = DefaultRetention.Keep
+    }
+    if r.Daily == 0 {
+        r.Daily = DefaultRetention.Daily
+    }
+    if r.Weekly == 0 {
+        r.Weekly = DefaultRetention.Weekly
+    }
+    return r
+}
+
+// keep picks the backups to keep from bs, which are sorted newest first.
+func (r Retention) keep(bs []Backup) map[string]bool {
+    r = r.withDefaults()
+    keep := make(map[string]bool)
+    days := make(map[string]bool)
+    weeks := make(map[string]bool)
+    for i, b := range bs {
+        if i < r.Keep {
+            keep[b.Path] = true
+        }
+        if day := b.Time.Format("2006-01-02"); !days[day] && len(days) < r.Daily {
+            days[day] = true
+            keep[b.Path] = true
+        }
+        y, w := b.Time.ISOWeek()
+        if week := fmt.Sprintf("%d-%02d", y, w); !weeks[week] && len(weeks) < r.Weekly {
+            weeks[week] = true
+            keep[b.Path] = true
+        }
+    }
+    return keep
+}
+
+// Backup is an earlier version of the data file.
+type Backup struct {
+    Path string
+    Time time.Time // when that version was saved
+    Size int64
+}
+
+// BackupStore is a Store that keeps earlier versions of the data.
+type BackupStore interface {
+    Backups() ([]Backup, error)
+    LoadBackup(ctx context.Context, b Backup) (*core.Data, error)
+    Restore(ctx context.Context, b Backup) error
+}
+
+// backupLayout names the backup files after the time the version was saved.
+const backupLayout = "2006-01-02T15-04-05.000"
+
+// backupDir is where the backups live: next to the data file, e.g.
+// tunesday.backups for tunesday.json.
+func (fs *FileStore) backupDir() string {
+    return strings.TrimSuffix(fs.path, filepath.Ext(fs.path)) + ".backups"
+}
+
+// Backups lists the backups, newest first.
+func (fs *FileStore) Backups() ([]Backup, error) {
+    entries, err := os.ReadDir(fs.backupDir())
+    if errors.Is(err, os.ErrNotExist) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var bs []Backup
+    for _, e := range entries {
+        stamp, ok := strings.CutSuffix(e.Name(), ".json")
+        if !ok || e.IsDir() {
+            continue
+        }
+        at, err := time.ParseInLocation(backupLayout, stamp, time.Local)
+        if err != nil {
+            continue
+        }
+        info, err := e.Info()
+        if err != nil {
+            return nil, err
+        }
+        bs = append(bs, Backup{Path: filepath.Join(fs.backupDir(), e.Name()), Time: at, Size: info.Size()})
+    }
+    sort.Slice(bs, func(i, j int) bool { return bs[i].Time.After(bs[j].Time) })
+    return bs, nil
+}
+
+func (fs *FileStore) LoadBackup(ctx context.Context, b Backup) (*core.Data, error) {
+    return fs.loadFile(b.Path)
+}
+
+// Restore makes b the current data. The data it replaces is backed up like
+// on every save, so a restore can be reverted.
+func (fs *FileStore) Restore(ctx context.Context, b Backup) error {
+    d, err := fs.LoadBackup(ctx, b)
+    if err != nil {
+        return err
+    }
+    return fs.Save(ctx, d)
+}
+
+// backup copies the data file to the backups and prunes those that are no
+// longer kept.
+func (fs *FileStore) backup() error {
+    info, err := os.Stat(fs.path)
+    if errors.Is(err, os.ErrNotExist) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    b, err := os.ReadFile(fs.path)
+    if err != nil {
+        return err
+    }
+    if !fs.current(b) && fs.codec != nil {
+        // the file is about to be rewritten, e.g. encrypted; its backup
+        // shouldn't stay readable for everyone
+        if b, err = fs.open(fs.path, b); err != nil {
+            return err
+        }
+        if b, err = fs.codec.Encode(b); err != nil {
+            return err
+        }
+    }
+    // backups are as private as the data file, and nobody else needs to
+    // see which there are
+    if err := os.MkdirAll(fs.backupDir(), 0o700); err != nil {
+        return err
+    }
+    name := info.ModTime().Local().Format(backupLayout) + ".json"
+    if err := writeFile(fs.sys, filepath.Join(fs.backupDir(), name), b, info.Mode().Perm()); err != nil {
+        return err
+    }
+
+    bs, err := fs.Backups()
+    if err != nil {
+        return err
+    }
+    keep := fs.retention.keep(bs)
+    for _, b := range bs {
+        if !keep[b.Path] {
+            if err := fs.sys.Remove(b.Path); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
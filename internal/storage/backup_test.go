@@ -0,0 +1,134 @@
+package storage
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "tunesday/internal/core"
+)
+
+func TestSaveRotatesBackups(t *testing.T) {
+    ctx := context.Background()
+    path := filepath.Join(t.TempDir(), "tunesday.json")
+    fs := NewFileStore(path)
+    fs.SetRetention(Retention{Keep: 2, Daily: 2, Weekly: 2})
+
+    // each version is saved at one of these times
+    times := []time.Time{
+        time.Date(2026, 10, 1, 10, 0, 0, 0, time.Local),
+        time.Date(2026, 10, 1, 11, 0, 0, 0, time.Local),
+        time.Date(2026, 10, 5, 10, 0, 0, 0, time.Local),
+        time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local),
+        time.Date(2026, 10, 13, 11, 0, 0, 0, time.Local),
+    }
+    d := core.NewData()
+    for i, at := range times {
+        d.Tunes = append(d.Tunes, core.Tune{Link: "tune", Name: at.Format(time.Kitchen)})
+        if err := fs.Save(ctx, d); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.Chtimes(path, at, at); err != nil {
+            t.Fatal(err)
+        }
+        if i == 2 {
+            // saving the same data again makes no backup
+            if err := fs.Save(ctx, d); err != nil {
+                t.Fatal(err)
+            }
+        }
+    }
+    d.Tunes = nil
+    if err := fs.Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+
+    bs, err := fs.Backups()
+    if err != nil {
+        t.Fatal(err)
+    }
+    // the last two saves, the newest of the last two days and weeks
+    want := []time.Time{times[4], times[3], times[2]}
+    if len(bs) != len(want) {
+        t.Fatalf("kept %d backups: %+v", len(bs), bs)
+    }
+    for i, b := range bs {
+        if !b.Time.Equal(want[i]) {
+            t.Errorf("backup %d is from %v, want %v", i, b.Time, want[i])
+        }
+    }
+
+    // restore the version of October 5th, which had three tunes
+    if err := fs.Restore(ctx, bs[2]); err != nil {
+        t.Fatal(err)
+    }
+    got, err := fs.Load(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(got.Tunes) != 3 {
+        t.Fatalf("restored %d tunes, want 3", len(got.Tunes))
+    }
+    after, _ := fs.Backups()
+    if prev, err := fs.LoadBackup(ctx, after[0]); err != nil || len(prev.Tunes) != 0 {
+        t.Fatalf("restoring did not back up the data it replaced: %+v, %v", prev, err)
+    }
+}
+
+func TestRetentionCanTurnOffKinds(t *testing.T) {
+    var bs []Backup
+    for i := 0; i < 30; i++ {
+        at := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local).AddDate(0, 0, -i)
+        bs = append(bs, Backup{Path: at.Format(backupLayout), Time: at})
+    }
+    for _, c := range []struct {
+        r    Retention
+        want int
+    }{
+        {Retention{}, 10 + 2},               // the days hold the daily ones, plus two older weeks
+        {Retention{Keep: 1, Weekly: -1}, 7}, // the last one is among the daily ones
+        {Retention{Keep: -1, Daily: -1, Weekly: 2}, 2},
+        {Retention{Keep: -1, Daily: -1, Weekly: -1}, 0},
+    } {
+        if got := len(c.r.keep(bs)); got != c.want {
+            t.Errorf("%+v keeps %d backups, want %d", c.r, got, c.want)
+        }
+    }
+}
+
+func TestBackupsAreAsPrivateAsTheData(t *testing.T) {
+    ctx := context.Background()
+    path := filepath.Join(t.TempDir(), "tunesday.json")
+    fs := NewFileStore(path)
+    d := core.NewData()
+    d.AddParticipant("Ann")
+    if err := fs.Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chmod(path, 0o600); err != nil {
+        t.Fatal(err)
+    }
+    d.AddParticipant("Bob")
+    if err := fs.Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+    if err := fs.SaveHistory(ctx, &core.History{}); err != nil {
+        t.Fatal(err)
+    }
+
+    bs, err := fs.Backups()
+    if err != nil || len(bs) != 1 {
+        t.Fatalf("backups = %v, %v", bs, err)
+    }
+    for f, want := range map[string]os.FileMode{bs[0].Path: 0o600, fs.historyPath(): 0o600, fs.backupDir(): 0o700} {
+        info, err := os.Stat(f)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if info.Mode().Perm() != want {
+            t.Errorf("%s has mode %v, want %v", f, info.Mode().Perm(), want)
+        }
+    }
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "reflect"
+    "sort"
+    "strings"
+    "unicode/utf8"
+
+    "tunesday/internal/core"
+)
+
+// DecodeError is a data file that can't be read, with the position of the
+// mistake so that it can be found when the file was edited by hand.
+type DecodeError struct {
+    Path   string
+    Line   int    // 1-based
+    Column int    // 1-based, in characters
+    Text   string // the line with the mistake
+    Err    error
+}
+
+func (e *DecodeError) Error() string {
+    return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// decode reads the data file contents b.
+func decode(path string, b []byte) (*core.Data, error) {
+    var d core.Data
+    if err := json.Unmarshal(b, &d); err != nil {
+        return nil, decodeError(path, b, err)
+    }
+    if d.Participants == nil {
+        d.Participants = map[string]*core.Participant{}
+    }
+    return &d, nil
+}
+
+// decodeError points err at its place in b, when the decoder says where.
+func decodeError(path string, b []byte, err error) error {
+    var offset int64
+    var se *json.SyntaxError
+    var te *json.UnmarshalTypeError
+    switch {
+    case errors.As(err, &se):
+        offset = se.Offset
+    case errors.As(err, &te):
+        offset = te.Offset
+        err = fmt.Errorf("%s: expected %s, found %s", te.Field, kindName(te.Type), te.Value)
+    default:
+        return fmt.Errorf("%s: %w", path, err)
+    }
+    // the offset is just past the character or value in question
+    at := int(offset) - 1
+    if at < 0 {
+        at = 0
+    }
+    if at > len(b) {
+        at = len(b)
+    }
+    line, col, text := position(b, at)
+    return &DecodeError{Path: path, Line: line, Column: col, Text: text, Err: err}
+}
+
+// position returns the line, column and text of the line of byte i of b.
+func position(b []byte, i int) (line, col int, text string) {
+    start := 0
+    line = 1
+    for j := 0; j < i && j < len(b); j++ {
+        if b[j] == '\n' {
+            line++
+            start = j + 1
+        }
+    }
+    end := start
+    for end < len(b) && b[end] != '\n' {
+        end++
+    }
+    col = utf8.RuneCount(b[start:min(i, end)]) + 1
+    return line, col, strings.TrimRight(string(b[start:end]), "\r")
+}
+
+func kindName(t reflect.Type) string {
+    if t == nil {
+        return "another value"
+    }
+    switch t.Kind() {
+    case reflect.String:
+        return "a string"
+    case reflect.Bool:
+        return "true or false"
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+        reflect.Float32, reflect.Float64:
+        return "a number"
+    case reflect.Slice, reflect.Array:
+        return "a list"
+    case reflect.Struct, reflect.Map, reflect.Pointer:
+        return "an object"
+    }
+    return t.String()
+}
+
+// CheckStore is a Store that can look for problems in the file it keeps,
+// beyond those in the data itself (see core.Data.Check).
+type CheckStore interface {
+    // Check loads the data like Load, but repairs what it can of a file that
+    // doesn't decode, and reports what it repaired. It fails with a
+    // *DecodeError when the file can't be repaired.
+    Check(ctx context.Context) (*core.Data, []core.Problem, error)
+}
+
+func (fs *FileStore) Check(ctx context.Context) (*core.Data, []core.Problem, error) {
+    b, err := fs.read(fs.path)
+    if errors.Is(err, os.ErrNotExist) {
+        return core.NewData(), nil, nil
+    }
+    if err != nil {
+        return nil, nil, err
+    }
+    var problems []core.Problem
+    d, err := decode(fs.path, b)
+    if err != nil {
+        fixed, commas := removeTrailingCommas(b)
+        if len(commas) == 0 {
+            return nil, nil, err
+        }
+        if d, err = decode(fs.path, fixed); err != nil {
+            return nil, nil, err
+        }
+        b = fixed
+        problems = append(problems, commas...)
+    }
+
+    // version 1 kept disabled participants by name; names that aren't
+    // participants are dropped when the file is upgraded
+    var legacy struct {
+        Disabled map[string]bool `json:"disabled"`
+    }
+    if json.Unmarshal(b, &legacy) == nil {
+        names := make([]string, 0, len(legacy.Disabled))
+        for name := range legacy.Disabled {
+            names = append(names, name)
+        }
+        sort.Strings(names)
+        for _, name := range names {
+            if d.ParticipantByName(name) == nil {
+                problems = append(problems, core.Problem{
+                    Text: fmt.Sprintf("disabled entry for unknown participant %q", name),
+                    Fix:  "drop it",
+                })
+            }
+        }
+    }
+    return d, problems, nil
+}
+
+// removeTrailingCommas drops commas right before a closing bracket or brace,
+// which JSON doesn't allow but is easily left behind when editing by hand.
+func removeTrailingCommas(b []byte) ([]byte, []core.Problem) {
+    var out []byte
+    var problems []core.Problem
+    inString, escaped := false, false
+    for i := 0; i < len(b); i++ {
+        c := b[i]
+        switch {
+        case inString:
+            switch {
+            case escaped:
+                escaped = false
+            case c == '\\':
+                escaped = true
+            case c == '"':
+                inString = false
+            }
+        case c == '"':
+            inString = true
+        case c == ',':
+            j := i + 1
+            for j < len(b) && strings.IndexByte(" \t\r\n", b[j]) >= 0 {
+                j++
+            }
+            if j < len(b) && (b[j] == '}' || b[j] == ']') {
+                line, col, _ := position(b, i)
+                problems = append(problems, core.Problem{
+                    Text: fmt.Sprintf("trailing comma at line %d, column %d", line, col),
+                    Fix:  "remove it",
+                })
+                continue
+            }
+        }
+        out = append(out, c)
+    }
+    return out, problems
+}
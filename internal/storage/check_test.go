@@ -0,0 +1,105 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestLoadReportsPositionOfMistake(t *testing.T) {
+    tests := []struct {
+        name, file   string
+        line, column int
+        text, err    string
+    }{
+        {
+            name:   "trailing comma",
+            file:   "{\n  \"participants\": {},\n  \"tunes\": [\n    {\"name\": \"Ä\"},\n  ]\n}\n",
+            line:   5,
+            column: 3,
+            text:   "  ]",
+            err:    "invalid character ']' looking for beginning of value",
+        },
+        {
+            name:   "wrong type",
+            file:   "{\"participants\": {},\n\"tunes\": [{\"seconds\": \"200\"}]}",
+            line:   2,
+            column: 27,
+            text:   "\"tunes\": [{\"seconds\": \"200\"}]}",
+            err:    "tunes.0.seconds: expected a number, found string",
+        },
+        {
+            name:   "wrong type in participants",
+            file:   "{\"version\": 2,\n\"participants\": {\"a\": {\"name\": 5}}}",
+            line:   2,
+            column: 32,
+            text:   "\"participants\": {\"a\": {\"name\": 5}}}",
+            err:    "participants.a.name: expected a string, found number",
+        },
+        {
+            name:   "cut off",
+            file:   "{\"participants\": {",
+            line:   1,
+            column: 18,
+            text:   "{\"participants\": {",
+            err:    "unexpected end of JSON input",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "tunesday.json")
+            if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+                t.Fatal(err)
+            }
+            _, err := NewFileStore(path).Load(context.Background())
+            var de *DecodeError
+            if !errors.As(err, &de) {
+                t.Fatalf("Load = %v, want a DecodeError", err)
+            }
+            if de.Line != tt.line || de.Column != tt.column || de.Text != tt.text || de.Err.Error() != tt.err {
+                t.Errorf("got %d:%d %q %q, want %d:%d %q %q", de.Line, de.Column, de.Text, de.Err, tt.line, tt.column, tt.text, tt.err)
+            }
+            if !strings.HasPrefix(err.Error(), path+":") {
+                t.Errorf("error %q doesn't name the file", err)
+            }
+        })
+    }
+}
+
+func TestCheckRepairsTrailingCommas(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "tunesday.json")
+    file := `{"participants": {"a": {"name": "Ann, Bob,]"},}, "disabled": {"Zed": true},
+"tunes": [{"name": "x"},
+]}`
+    if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    d, problems, err := NewFileStore(path).Check(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    var got []string
+    for _, p := range problems {
+        got = append(got, p.String())
+    }
+    want := "trailing comma at line 1, column 46 (fix: remove it)|" +
+        "trailing comma at line 2, column 24 (fix: remove it)|" +
+        `disabled entry for unknown participant "Zed" (fix: drop it)`
+    if strings.Join(got, "|") != want {
+        t.Errorf("problems = %q", got)
+    }
+    if d.Participants["a"].Name != "Ann, Bob,]" || len(d.Tunes) != 1 {
+        t.Errorf("data = %+v", d)
+    }
+
+    if err := os.WriteFile(path, []byte(`{"tunes": [}`), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    var de *DecodeError
+    if _, _, err := NewFileStore(path).Check(context.Background()); !errors.As(err, &de) {
+        t.Errorf("Check of a broken file = %v, want a DecodeError", err)
+    }
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+)
+
+// Codec changes the bytes of the files a store keeps, e.g. to encrypt them
+// (see Encrypted).
+type Codec interface {
+    // Encode turns what is stored into what is written.
+    Encode(b []byte) ([]byte, error)
+    // Decode turns the file read from path back into what was stored. It
+    // takes the files written before the codec was in use too.
+    Decode(path string, b []byte) ([]byte, error)
+    // Current tells whether b was written the way Encode writes now.
+    Current(b []byte) bool
+}
+
+// CodecStore is a Store that keeps its data, its history and its backups as
+// files whose bytes a Codec can change. Wrappers like Encrypted build on it.
+type CodecStore interface {
+    Store
+    HistoryStore
+    BackupStore
+    CheckStore
+    // SetCodec makes the store read and write its files through c.
+    SetCodec(c Codec)
+    // Recode rewrites all files of the store with c, which is used from then
+    // on. They are all read and written aside first and only replaced once
+    // that worked, so a failure leaves every file as it was.
+    Recode(ctx context.Context, c Codec) error
+    // Stored returns the data file as it is written.
+    Stored() ([]byte, error)
+}
+
+func (fs *FileStore) SetCodec(c Codec) { fs.codec = c }
+
+func (fs *FileStore) Stored() ([]byte, error) { return os.ReadFile(fs.path) }
+
+func (fs *FileStore) Recode(ctx context.Context, c Codec) error {
+    paths := []string{fs.historyPath()}
+    bs, err := fs.Backups()
+    if err != nil {
+        return err
+    }
+    for _, b := range bs {
+        paths = append(paths, b.Path)
+    }
+    // the data file goes last, so that a failure while renaming leaves the
+    // fewest files to put back
+    paths = append(paths, fs.path)
+
+    type rewrite struct {
+        staged
+        old []byte
+    }
+    var rewrites []rewrite
+    discard := func(rs []rewrite) {
+        for _, r := range rs {
+            r.discard(fs.sys)
+        }
+    }
+    for _, path := range paths {
+        old, err := os.ReadFile(path)
+        if errors.Is(err, os.ErrNotExist) {
+            continue
+        }
+        if err != nil {
+            discard(rewrites)
+            return err
+        }
+        b, err := fs.open(path, old)
+        if err == nil {
+            b, err = c.Encode(b)
+        }
+        var s staged
+        if err == nil {
+            s, err = stageFile(fs.sys, path, b, fs.perm())
+        }
+        if err != nil {
+            discard(rewrites)
+            return err
+        }
+        rewrites = append(rewrites, rewrite{s, old})
+    }
+
+    for i, r := range rewrites {
+        if err := r.commit(fs.sys); err != nil {
+            discard(rewrites[i+1:])
+            // the files replaced so far go back to what they were, so that
+            // all of them still open with the codec in use
+            for _, r := range rewrites[:i+1] {
+                if rerr := writeFile(fs.sys, r.target, r.old, fs.perm()); rerr != nil {
+                    return fmt.Errorf("%w; putting back %s failed too: %v", err, r.target, rerr)
+                }
+            }
+            return err
+        }
+    }
+    fs.codec = c
+    return nil
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+    "bytes"
+    "context"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "sync"
+
+    "golang.org/x/crypto/pbkdf2"
+)
+
+// sealedFormat marks an encrypted file; the number goes up when the way files
+// are encrypted changes.
+const sealedFormat = "tunesday-encrypted/1"
+
+// keyIterations is how often PBKDF2 hashes the passphrase for new files,
+// which is what makes guessing passphrases slow. Files keep their count, so
+// raising it doesn't lock out older files.
+var keyIterations = 600_000
+
+var (
+    // ErrEncrypted is returned when a file is encrypted and the store has
+    // no key.
+    ErrEncrypted = errors.New("the file is encrypted and there is no passphrase to open it")
+    // ErrWrongKey is returned when the key can't open a file: the
+    // passphrase is wrong, or the file was changed.
+    ErrWrongKey = errors.New("the passphrase doesn't open the file, or the file was changed")
+)
+
+// Key encrypts files with AES-256-GCM under a key derived from a passphrase
+// with PBKDF2-SHA256. Every file has its own nonce; the salt is picked once
+// per key, so the slow derivation runs only once per passphrase and salt.
+type Key struct {
+    passphrase []byte
+
+    mu      sync.Mutex
+    salt    []byte                 // for the files this key writes
+    derived map[string]cipher.AEAD // by salt and iterations
+}
+
+func NewKey(passphrase string) (*Key, error) {
+    if passphrase == "" {
+        return nil, errors.New("the passphrase is empty")
+    }
+    return &Key{passphrase: []byte(passphrase), derived: make(map[string]cipher.AEAD)}, nil
+}
+
+// sealed is an encrypted file. It stays JSON so that it is recognised as
+// tunesday's, and so that the settings needed to open it travel with it.
+type sealed struct {
+    Format     string `json:"format"`
+    KDF        string `json:"kdf"`
+    Iterations int    `json:"iterations"`
+    Salt       []byte `json:"salt"`
+    Nonce      []byte `json:"nonce"`
+    Data       []byte `json:"data"`
+}
+
+// header is authenticated along with the data, so none of the settings can
+// be swapped unnoticed.
+func (s *sealed) header() []byte {
+    return fmt.Appendf(nil, "%s %s %d %x", s.Format, s.KDF, s.Iterations, s.Salt)
+}
+
+// isSealed tells whether b is an encrypted file.
+func isSealed(b []byte) bool {
+    if !bytes.Contains(b, []byte(sealedFormat)) {
+        return false
+    }
+    var s sealed
+    return json.Unmarshal(b, &s) == nil && s.Format == sealedFormat
+}
+
+func (k *Key) seal(plain []byte) ([]byte, error) {
+    k.mu.Lock()
+    if k.salt == nil {
+        k.salt = make([]byte, 16)
+        if _, err := rand.Read(k.salt); err != nil {
+            k.mu.Unlock()
+            return nil, err
+        }
+    }
+    s := sealed{Format: sealedFormat, KDF: "pbkdf2-sha256", Iterations: keyIterations, Salt: k.salt}
+    k.mu.Unlock()
+
+    aead, err := k.aead(s.Salt, s.Iterations)
+    if err != nil {
+        return nil, err
+    }
+    s.Nonce = make([]byte, aead.NonceSize())
+    if _, err := rand.Read(s.Nonce); err != nil {
+        return nil, err
+    }
+    s.Data = aead.Seal(nil, s.Nonce, plain, s.header())
+    return encodeJSON(s)
+}
+
+func (k *Key) open(b []byte) ([]byte, error) {
+    var s sealed
+    if err := json.Unmarshal(b, &s); err != nil {
+        return nil, err
+    }
+    switch {
+    case s.Format != sealedFormat:
+        return nil, fmt.Errorf("unknown encryption %q", s.Format)
+    case s.KDF != "pbkdf2-sha256":
+        return nil, fmt.Errorf("unknown key derivation %q", s.KDF)
+    case s.Iterations < 1 || s.Iterations > 100*keyIterations:
+        return nil, fmt.Errorf("%d key derivation rounds are out of range", s.Iterations)
+    }
+    aead, err := k.aead(s.Salt, s.Iterations)
+    if err != nil {
+        return nil, err
+    }
+    if len(s.Nonce) != aead.NonceSize() {
+        return nil, ErrWrongKey
+    }
+    plain, err := aead.Open(nil, s.Nonce, s.Data, s.header())
+    if err != nil {
+        return nil, ErrWrongKey
+    }
+    return plain, nil
+}
+
+func (k *Key) aead(salt []byte, iterations int) (cipher.AEAD, error) {
+    k.mu.Lock()
+    defer k.mu.Unlock()
+    id := fmt.Sprintf("%x/%d", salt, iterations)
+    if aead, ok := k.derived[id]; ok {
+        return aead, nil
+    }
+    block, err := aes.NewCipher(deriveKey(k.passphrase, salt, iterations))
+    if err != nil {
+        return nil, err
+    }
+    aead, err := cipher.NewGCM(block)
+    if err != nil {
+        return nil, err
+    }
+    k.derived[id] = aead
+    return aead, nil
+}
+
+// deriveKey derives an AES-256 key from passphrase with PBKDF2-SHA256.
+func deriveKey(passphrase, salt []byte, iterations int) []byte {
+    return pbkdf2.Key(passphrase, salt, iterations, 32, sha256.New)
+}
+
+// KeyStore is a Store that can encrypt its files.
+type KeyStore interface {
+    // Encrypted tells whether the data file is encrypted.
+    Encrypted() (bool, error)
+    // Rekey encrypts the data file, its history and its backups with k, or
+    // decrypts them when k is nil. Either all of them change or none do.
+    Rekey(ctx context.Context, k *Key) error
+}
+
+// EncryptedStore encrypts the files of the store it wraps.
+type EncryptedStore struct {
+    CodecStore
+    key *Key
+}
+
+// Encrypted wraps inner so that its files are encrypted once there is a key,
+// from SetKey or Rekey. Until then they are written as they are, and
+// encrypted files can't be read.
+func Encrypted(inner CodecStore) *EncryptedStore {
+    inner.SetCodec(keyCodec{})
+    return &EncryptedStore{CodecStore: inner}
+}
+
+// SetKey makes the store encrypt the files it writes with k. Files that
+// aren't encrypted can still be read, so setting a key for an existing file
+// encrypts it on the next save; Rekey encrypts the backups too.
+func (e *EncryptedStore) SetKey(k *Key) {
+    e.key = k
+    e.CodecStore.SetCodec(keyCodec{k})
+}
+
+func (e *EncryptedStore) Encrypted() (bool, error) {
+    b, err := e.CodecStore.Stored()
+    if errors.Is(err, os.ErrNotExist) {
+        return e.key != nil, nil
+    }
+    if err != nil {
+        return false, err
+    }
+    return isSealed(b), nil
+}
+
+func (e *EncryptedStore) Rekey(ctx context.Context, k *Key) error {
+    if err := e.CodecStore.Recode(ctx, keyCodec{k}); err != nil {
+        return err
+    }
+    e.key = k
+    return nil
+}
+
+// keyCodec encrypts files with key, or leaves them plain without one.
+type keyCodec struct {
+    key *Key
+}
+
+func (c keyCodec) Encode(b []byte) ([]byte, error) {
+    if c.key == nil {
+        return b, nil
+    }
+    return c.key.seal(b)
+}
+
+func (c keyCodec) Decode(path string, b []byte) ([]byte, error) {
+    if !isSealed(b) {
+        return b, nil
+    }
+    if c.key == nil {
+        return nil, fmt.Errorf("%s: %w", path, ErrEncrypted)
+    }
+    plain, err := c.key.open(b)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    return plain, nil
+}
+
+func (c keyCodec) Current(b []byte) bool { return isSealed(b) == (c.key != nil) }
@@ -0,0 +1,243 @@
+package storage
+
+import (
+    "bytes"
+    "context"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "tunesday/internal/core"
+)
+
+// fastKeys makes new keys cheap to derive for the duration of a test.
+func fastKeys(t *testing.T) {
+    n := keyIterations
+    keyIterations = 1000
+    t.Cleanup(func() { keyIterations = n })
+}
+
+func mustKey(t *testing.T, passphrase string) *Key {
+    t.Helper()
+    k, err := NewKey(passphrase)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return k
+}
+
+// keyedStore opens path encrypted with passphrase.
+func keyedStore(t *testing.T, path, passphrase string) *EncryptedStore {
+    t.Helper()
+    s := Encrypted(NewFileStore(path))
+    s.SetKey(mustKey(t, passphrase))
+    return s
+}
+
+func TestDeriveKey(t *testing.T) {
+    // RFC 7914, section 11; files written before keep opening only as long
+    // as the key is derived the same way
+    for _, c := range []struct {
+        password, salt string
+        iterations     int
+        want           string
+    }{
+        {"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+        {"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+    } {
+        got := hex.EncodeToString(deriveKey([]byte(c.password), []byte(c.salt), c.iterations))
+        if got != c.want[:64] {
+            t.Errorf("deriveKey(%q, %q, %d) = %s, want %s", c.password, c.salt, c.iterations, got, c.want[:64])
+        }
+    }
+}
+
+func TestEncryptedStore(t *testing.T) {
+    fastKeys(t)
+    ctx := context.Background()
+    path := filepath.Join(t.TempDir(), "tunesday.json")
+    d := core.NewData()
+    d.AddParticipant("Ann")
+
+    // a file from before encryption is read, and encrypted on the next save
+    // along with its backup
+    if err := NewFileStore(path).Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+    store := keyedStore(t, path, "correct horse")
+    if _, err := store.Load(ctx); err != nil {
+        t.Fatal(err)
+    }
+    d.AddParticipant("Bob")
+    if err := store.Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+    if err := store.SaveHistory(ctx, &core.History{}); err != nil {
+        t.Fatal(err)
+    }
+    bs, _ := store.Backups()
+    for _, f := range []string{path, NewFileStore(path).historyPath(), bs[0].Path} {
+        b, err := os.ReadFile(f)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if !isSealed(b) || bytes.Contains(b, []byte("Ann")) {
+            t.Errorf("%s is not encrypted:\n%s", f, b)
+        }
+    }
+    if enc, err := store.Encrypted(); !enc || err != nil {
+        t.Errorf("Encrypted = %v, %v", enc, err)
+    }
+
+    // saving the same data again leaves the file alone
+    before, _ := os.ReadFile(path)
+    if err := store.Save(ctx, d); err != nil {
+        t.Fatal(err)
+    }
+    if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+        t.Error("saving unchanged data rewrote the file")
+    }
+
+    reopened := keyedStore(t, path, "correct horse")
+    got, err := reopened.Load(ctx)
+    if err != nil || got.ParticipantByName("Bob") == nil {
+        t.Fatalf("Load = %v, %v", got, err)
+    }
+    if _, err := NewFileStore(path).Load(ctx); !errors.Is(err, ErrEncrypted) {
+        t.Errorf("Load without a key = %v, want ErrEncrypted", err)
+    }
+    wrong := keyedStore(t, path, "battery staple")
+    if _, err := wrong.Load(ctx); !errors.Is(err, ErrWrongKey) {
+        t.Errorf("Load with the wrong key = %v, want ErrWrongKey", err)
+    }
+
+    // the settings in the file are authenticated too
+    var s sealed
+    json.Unmarshal(before, &s)
+    s.Iterations++
+    tampered, _ := json.Marshal(s)
+    if err := os.WriteFile(path, tampered, 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := reopened.Load(ctx); !errors.Is(err, ErrWrongKey) {
+        t.Errorf("Load of a tampered file = %v, want ErrWrongKey", err)
+    }
+}
+
+func TestRekey(t *testing.T) {
+    fastKeys(t)
+    ctx := context.Background()
+    path := filepath.Join(t.TempDir(), "tunesday.json")
+    store := keyedStore(t, path, "old")
+    d := core.NewData()
+    for _, name := range []string{"Ann", "Bob"} {
+        d.AddParticipant(name)
+        if err := store.Save(ctx, d); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := store.SaveHistory(ctx, &core.History{}); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := store.Rekey(ctx, mustKey(t, "new")); err != nil {
+        t.Fatal(err)
+    }
+    old := keyedStore(t, path, "old")
+    if _, err := old.Load(ctx); !errors.Is(err, ErrWrongKey) {
+        t.Errorf("Load with the old key = %v, want ErrWrongKey", err)
+    }
+    rotated := keyedStore(t, path, "new")
+    if _, err := rotated.LoadHistory(ctx); err != nil {
+        t.Errorf("history: %v", err)
+    }
+    bs, _ := rotated.Backups()
+    if len(bs) != 1 {
+        t.Fatalf("backups = %v", bs)
+    }
+    if _, err := rotated.LoadBackup(ctx, bs[0]); err != nil {
+        t.Errorf("backup: %v", err)
+    }
+
+    // without a key, everything is plain JSON again
+    if err := rotated.Rekey(ctx, nil); err != nil {
+        t.Fatal(err)
+    }
+    for _, f := range []string{path, NewFileStore(path).historyPath(), bs[0].Path} {
+        if b, _ := os.ReadFile(f); isSealed(b) {
+            t.Errorf("%s is still encrypted", f)
+        }
+    }
+    if got, err := NewFileStore(path).Load(ctx); err != nil || len(got.Participants) != 2 {
+        t.Errorf("Load = %v, %v", got, err)
+    }
+}
+
+// renameFS is the real file system with the nth rename failing.
+type renameFS struct {
+    osFS
+    n *int
+}
+
+func (f renameFS) Rename(oldpath, newpath string) error {
+    *f.n--
+    if *f.n == 0 {
+        return errInjected
+    }
+    return f.osFS.Rename(oldpath, newpath)
+}
+
+func TestFailedRekeyChangesNothing(t *testing.T) {
+    fastKeys(t)
+    ctx := context.Background()
+    for name, sys := range map[string]func() fileSystem{
+        "write":    func() fileSystem { return failFS{step: "write"} },
+        "rename 2": func() fileSystem { n := 2; return renameFS{n: &n} },
+        "rename 3": func() fileSystem { n := 3; return renameFS{n: &n} },
+    } {
+        t.Run(name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "tunesday.json")
+            fs := NewFileStore(path)
+            store := Encrypted(fs)
+            store.SetKey(mustKey(t, "old"))
+            d := core.NewData()
+            for _, name := range []string{"Ann", "Bob"} {
+                d.AddParticipant(name)
+                if err := store.Save(ctx, d); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            if err := store.SaveHistory(ctx, &core.History{}); err != nil {
+                t.Fatal(err)
+            }
+
+            fs.sys = sys()
+            if err := store.Rekey(ctx, mustKey(t, "new")); !errors.Is(err, errInjected) {
+                t.Fatalf("Rekey = %v, want the injected failure", err)
+            }
+            fs.sys = osFS{}
+            // the store goes on with the old key, and so do all files
+            if err := store.Save(ctx, d); err != nil {
+                t.Fatal(err)
+            }
+            old := keyedStore(t, path, "old")
+            if _, err := old.Load(ctx); err != nil {
+                t.Errorf("data: %v", err)
+            }
+            if _, err := old.LoadHistory(ctx); err != nil {
+                t.Errorf("history: %v", err)
+            }
+            bs, _ := old.Backups()
+            for _, b := range bs {
+                if _, err := old.LoadBackup(ctx, b); err != nil {
+                    t.Errorf("backup: %v", err)
+                }
+            }
+            assertNoTempFiles(t, filepath.Dir(path))
+            assertNoTempFiles(t, fs.backupDir())
+        })
+    }
+}
@@ -1,54 +1,169 @@
 package storage
 
 import (
+    "bytes"
     "context"
     "encoding/json"
     "errors"
+    "fmt"
     "os"
+    "path/filepath"
+    "strings"
 
     "tunesday/internal/core"
 )
 
-type FileStore struct{ path string }
+// FileStore keeps the data in a JSON file and backs up every version it
+// replaces, see Retention.
+type FileStore struct {
+    path      string
+    retention Retention
+    sys       fileSystem
+    codec     Codec // changes the bytes of the files when set
+}
+
+func NewFileStore(path string) *FileStore {
+    return &FileStore{path: path, retention: DefaultRetention, sys: osFS{}}
+}
 
-func NewFileStore(path string) *FileStore { return &FileStore{path: path} }
+// SetRetention changes which backups are kept.
+func (fs *FileStore) SetRetention(r Retention) { fs.retention = r }
 
 func (fs *FileStore) Load(ctx context.Context) (*core.Data, error) {
-    f, err := os.Open(fs.path)
+    d, err := fs.loadFile(fs.path)
     if errors.Is(err, os.ErrNotExist) {
         return core.NewData(), nil
     }
+    return d, err
+}
+
+func (fs *FileStore) loadFile(path string) (*core.Data, error) {
+    b, err := fs.read(path)
     if err != nil {
         return nil, err
     }
-    defer f.Close()
-    dec := json.NewDecoder(f)
-    var d core.Data
-    if err := dec.Decode(&d); err != nil {
-        return nil, err
-    }
-    if d.Participants == nil {
-        d.Participants = map[string]int{}
-    }
-    return &d, nil
+    return decode(path, b)
 }
 
+// Save writes d unless the file already holds exactly that. The version it
+// replaces goes to the backups first; if that fails, d is saved anyway and the
+// error is returned.
 func (fs *FileStore) Save(ctx context.Context, d *core.Data) error {
-    tmp := fs.path + ".tmp"
-    f, err := os.Create(tmp)
+    b, err := encodeJSON(d)
     if err != nil {
         return err
     }
-    enc := json.NewEncoder(f)
-    enc.SetIndent("", "  ")
-    if err := enc.Encode(d); err != nil {
-        f.Close()
-        _ = os.Remove(tmp)
+    if fs.holds(b) {
+        return nil
+    }
+    berr := fs.backup()
+    if err := fs.write(fs.path, b); err != nil {
         return err
     }
-    if err := f.Close(); err != nil {
-        _ = os.Remove(tmp)
+    if berr != nil {
+        return fmt.Errorf("backup: %w", berr)
+    }
+    return nil
+}
+
+// holds tells whether the data file already holds b, written the way the
+// codec writes now.
+func (fs *FileStore) holds(b []byte) bool {
+    old, err := os.ReadFile(fs.path)
+    if err != nil || !fs.current(old) {
+        return false
+    }
+    if old, err = fs.open(fs.path, old); err != nil {
+        return false
+    }
+    return bytes.Equal(old, b)
+}
+
+// read reads a file of the store, through the codec.
+func (fs *FileStore) read(path string) ([]byte, error) {
+    b, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return fs.open(path, b)
+}
+
+// open decodes the file b read from path. Without a codec, encrypted files
+// are refused rather than taken for empty data.
+func (fs *FileStore) open(path string, b []byte) ([]byte, error) {
+    if fs.codec != nil {
+        return fs.codec.Decode(path, b)
+    }
+    if isSealed(b) {
+        return nil, fmt.Errorf("%s: %w", path, ErrEncrypted)
+    }
+    return b, nil
+}
+
+// current tells whether the file b is written the way the store writes now.
+func (fs *FileStore) current(b []byte) bool {
+    if fs.codec != nil {
+        return fs.codec.Current(b)
+    }
+    return !isSealed(b)
+}
+
+// write writes a file of the store, through the codec.
+func (fs *FileStore) write(path string, b []byte) error {
+    if fs.codec != nil {
+        var err error
+        if b, err = fs.codec.Encode(b); err != nil {
+            return err
+        }
+    }
+    return writeFile(fs.sys, path, b, fs.perm())
+}
+
+// historyPath is where the undo history lives: next to the data file, e.g.
+// tunesday.history.json for tunesday.json.
+func (fs *FileStore) historyPath() string {
+    return strings.TrimSuffix(fs.path, filepath.Ext(fs.path)) + ".history.json"
+}
+
+// perm is the mode for new files of the store: that of the data file, since
+// they hold copies of it, or 0644 before there is one.
+func (fs *FileStore) perm() os.FileMode {
+    if info, err := fs.sys.Stat(fs.path); err == nil {
+        return info.Mode().Perm()
+    }
+    return 0o644
+}
+
+// LoadHistory reads the undo history, which is empty when there is none yet.
+func (fs *FileStore) LoadHistory(ctx context.Context) (*core.History, error) {
+    b, err := fs.read(fs.historyPath())
+    if errors.Is(err, os.ErrNotExist) {
+        return &core.History{}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var h core.History
+    if err := json.Unmarshal(b, &h); err != nil {
+        return nil, err
+    }
+    return &h, nil
+}
+
+func (fs *FileStore) SaveHistory(ctx context.Context, h *core.History) error {
+    b, err := encodeJSON(h)
+    if err != nil {
         return err
     }
-    return os.Rename(tmp, fs.path)
+    return fs.write(fs.historyPath(), b)
+}
+
+func encodeJSON(v any) ([]byte, error) {
+    var buf bytes.Buffer
+    enc := json.NewEncoder(&buf)
+    enc.SetIndent("", "  ")
+    if err := enc.Encode(v); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
 }
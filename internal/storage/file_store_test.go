@@ -5,6 +5,7 @@ import (
     "os"
     "path/filepath"
     "testing"
+    "time"
 
     "tunesday/internal/core"
 )
@@ -28,7 +29,7 @@ func TestSaveAndLoadRoundTrip(t *testing.T) {
     fs := NewFileStore(path)
 
     in := &core.Data{
-        Participants: map[string]int{"Bob": 1},
+        Participants: map[string]*core.Participant{"b": {ID: "b", Name: "Bob", LegacyDraws: 1}},
         Tunes: []core.Tune{
             {
                 Name:     "Never Gonna Give You Up",
@@ -55,7 +56,7 @@ func TestSaveAndLoadRoundTrip(t *testing.T) {
     if err != nil {
         t.Fatalf("Load error: %v", err)
     }
-    if out.Participants["Bob"] != 1 {
+    if p := out.Participants["b"]; p == nil || p.Name != "Bob" || p.LegacyDraws != 1 {
         t.Fatalf("unexpected content after round trip: %#v", out)
     }
     if len(out.Tunes) != 2 {
@@ -68,3 +69,46 @@ func TestSaveAndLoadRoundTrip(t *testing.T) {
         t.Fatalf("tune[1] mismatch after round trip: got %+v want %+v", out.Tunes[1], in.Tunes[1])
     }
 }
+
+func TestLoadMigratesLegacyParticipants(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "legacy.json")
+    legacy := `{"participants":{"Ann":3,"Bob":1},"disabled":{"Bob":true},"tunes":[]}`
+    if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    d, err := NewFileStore(path).Load(context.Background())
+    if err != nil {
+        t.Fatalf("Load error: %v", err)
+    }
+    if d.Version != core.CurrentVersion {
+        t.Fatalf("version = %d, want %d", d.Version, core.CurrentVersion)
+    }
+    bob := d.ParticipantByName("Bob")
+    if bob == nil || !bob.Disabled || bob.LegacyDraws != 1 {
+        t.Fatalf("unexpected Bob after migration: %+v", bob)
+    }
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+    dir := t.TempDir()
+    fs := NewFileStore(filepath.Join(dir, "tunesday.json"))
+    h, err := fs.LoadHistory(context.Background())
+    if err != nil || h == nil || len(h.Done) != 0 {
+        t.Fatalf("LoadHistory without a file = %+v, %v", h, err)
+    }
+    _ = h.Record("Add Ann", core.NewData(), time.Now())
+    if err := fs.SaveHistory(context.Background(), h); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "tunesday.history.json")); err != nil {
+        t.Fatalf("history file: %v", err)
+    }
+    got, err := fs.LoadHistory(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if c, ok := got.LastUndo(); !ok || c.Action != "Add Ann" {
+        t.Fatalf("history after round trip = %+v", got)
+    }
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package storage
+
+import "io/fs"
+
+// fileOwner reports no owner; files here have none to preserve.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+    return -1, -1, false
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package storage
+
+import (
+    "io/fs"
+    "syscall"
+)
+
+// fileOwner returns the user and group owning a file.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+    st, ok := info.Sys().(*syscall.Stat_t)
+    if !ok {
+        return -1, -1, false
+    }
+    return int(st.Uid), int(st.Gid), true
+}
@@ -10,3 +10,9 @@ type Store interface {
     Load(ctx context.Context) (*core.Data, error)
     Save(ctx context.Context, d *core.Data) error
 }
+
+// HistoryStore keeps the undo history of a Store.
+type HistoryStore interface {
+    LoadHistory(ctx context.Context) (*core.History, error)
+    SaveHistory(ctx context.Context, h *core.History) error
+}
@@ -1,49 +1,169 @@
 package termui
 
-import "fmt"
+import (
+    "fmt"
+    "strings"
+
+    "tunesday/internal/i18n"
+)
+
+// sessionTheme is shown below the header while a themed round is running.
+var sessionTheme string
+
+// SetSessionTheme sets the theme shown below the Tunesday header; "" hides it.
+func SetSessionTheme(theme string) { sessionTheme = theme }
+
+// header is the text of a header: lines drawn in big letters and a short
+// line for the compact header. Both are translated when drawn.
+type header struct {
+    big   []string
+    small string
+}
+
+var (
+    tunesdayHeader    = header{big: []string{"IT'S...", "HAPPY TUNESDAY !!"}, small: "♫ It's Tunesday! ♫"}
+    notTunesdayHeader = header{big: []string{"!! IT'S NOT TUNESDAY !!"}, small: "♫ It's not Tunesday! ♫"}
+    radioHeader       = header{big: []string{"TUNESDAY RADIO"}, small: "♫ Tunesday Radio ♫"}
+)
+
+// headerStyles are the header styles a skin can choose from. Each draws a
+// header centred on a terminal width columns wide.
+var headerStyles = map[string]func(h header, width int) []string{
+    "classic": bigBanner,
+    "compact": compactBanner,
+}
+
+// bigBanner draws the header in big letters in a box. On narrow terminals
+// it shrinks the margins, then breaks the text between words, and falls back
+// to the compact header when a single word doesn't fit.
+func bigBanner(h header, width int) []string {
+    big := make([]string, len(h.big))
+    for i, l := range h.big {
+        big[i] = i18n.T(l)
+    }
+    if box := bannerBox(big, 5, 3, width); box != nil {
+        return box
+    }
+    if box := bannerBox(big, 1, 1, width); box != nil {
+        return box
+    }
+    if box := bannerBox(wrapBig(big, width-6), 1, 1, width); box != nil {
+        return box
+    }
+    return compactBanner(h, width)
+}
+
+// bannerBox frames the big lines with side spaces left and right and pad
+// empty lines above and below, or returns nil if that is wider than width.
+func bannerBox(lines []string, side, pad, width int) []string {
+    if len(lines) == 0 {
+        return nil
+    }
+    textW := 0
+    for _, l := range lines {
+        if w := bigFont.Width(l); w > textW {
+            textW = w
+        }
+    }
+    inner := textW + 2*side
+    if inner+4 > width {
+        return nil
+    }
+    border := strings.Repeat("█", inner+4)
+    empty := "█▌" + strings.Repeat(" ", inner) + "▐█"
+    out := []string{border}
+    for i := 0; i < pad; i++ {
+        out = append(out, empty)
+    }
+    margin := strings.Repeat(" ", side)
+    for _, l := range lines {
+        for _, row := range bigFont.Render(l) {
+            out = append(out, "█▌"+margin+PadRight(row, textW)+margin+"▐█")
+        }
+    }
+    for i := 0; i < pad; i++ {
+        out = append(out, empty)
+    }
+    out = append(out, border)
+    for i := range out {
+        out[i] = centerText(width, out[i])
+    }
+    return out
+}
+
+// wrapBig breaks lines between words so each fits width columns in big
+// letters. It returns nil if a single word is wider than that.
+func wrapBig(lines []string, width int) []string {
+    var out []string
+    for _, l := range lines {
+        cur := ""
+        for _, word := range strings.Fields(l) {
+            if bigFont.Width(word) > width {
+                return nil
+            }
+            if cur != "" && bigFont.Width(cur+" "+word) <= width {
+                cur += " " + word
+                continue
+            }
+            if cur != "" {
+                out = append(out, cur)
+            }
+            cur = word
+        }
+        if cur != "" {
+            out = append(out, cur)
+        }
+    }
+    return out
+}
+
+// compactBanner draws the short header text in a thin box.
+func compactBanner(h header, width int) []string {
+    small := i18n.T(h.small)
+    text := "   " + small + "   "
+    if n := len([]rune(text)); n+2 > width {
+        text = TruncateRunes(small, width-2)
+    }
+    line := strings.Repeat("─", len([]rune(text)))
+    return []string{
+        centerText(width, "╭"+line+"╮"),
+        centerText(width, "│"+text+"│"),
+        centerText(width, "╰"+line+"╯"),
+    }
+}
+
+// headerLines draws h in the skin's header style for the terminal width.
+func headerLines(h header) []string {
+    style, ok := headerStyles[skin.Header]
+    if !ok {
+        style = bigBanner
+    }
+    return style(h, termWidth())
+}
+
+func printHeader(h header) {
+    for _, line := range headerLines(h) {
+        fmt.Fprintln(con.Out, skin.Logo.Render(line))
+    }
+}
+
+// headerHeight is the number of lines PrintTunesdayHeader prints.
+func headerHeight() int {
+    n := len(headerLines(tunesdayHeader)) + 1
+    if sessionTheme != "" {
+        n++
+    }
+    return n
+}
 
 func PrintTunesdayHeader() {
-    fmt.Println(`██████████████████████████████████████████████████████████████████████████`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`█▌     ░▀█▀░▀█▀░▀░█▀▀░░░░░░░░░                                          ▐█`)
-    fmt.Println(`█▌     ░░█░░░█░░░░▀▀█░░░░░░░░░                                          ▐█`)
-    fmt.Println(`█▌     ░▀▀▀░░▀░░░░▀▀▀░▀░░▀░░▀░                                          ▐█`)
-    fmt.Println(`█▌     ░█░█░█▀█░█▀█░█▀█░█░█░░░▀█▀░█░█░█▀█░█▀▀░█▀▀░█▀▄░█▀█░█░█░░░█░█     ▐█`)
-    fmt.Println(`█▌     ░█▀█░█▀█░█▀▀░█▀▀░░█░░░░░█░░█░█░█░█░█▀▀░▀▀█░█░█░█▀█░░█░░░░▀░▀     ▐█`)
-    fmt.Println(`█▌     ░▀░▀░▀░▀░▀░░░▀░░░░▀░░░░░▀░░▀▀▀░▀░▀░▀▀▀░▀▀▀░▀▀░░▀░▀░░▀░░░░▀░▀     ▐█`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`█▌                                                                      ▐█`)
-    fmt.Println(`██████████████████████████████████████████████████████████████████████████`)
-    fmt.Println("")
-}
-
-func PrintNotTunesdayHeader() {
-    fmt.Println(`████████████████████████████████████████████████████████████████████████████████████████`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`█▌     ░█░█░░░▀█▀░▀█▀░▀░█▀▀░░░█▀█░█▀█░▀█▀░░░▀█▀░█░█░█▀█░█▀▀░█▀▀░█▀▄░█▀█░█░█░░░█░█     ▐█`)
-    fmt.Println(`█▌     ░▀░▀░░░░█░░░█░░░░▀▀█░░░█░█░█░█░░█░░░░░█░░█░█░█░█░█▀▀░▀▀█░█░█░█▀█░░█░░░░▀░▀     ▐█`)
-    fmt.Println(`█▌     ░▀░▀░░░▀▀▀░░▀░░░░▀▀▀░░░▀░▀░▀▀▀░░▀░░░░░▀░░▀▀▀░▀░▀░▀▀▀░▀▀▀░▀▀░░▀░▀░░▀░░░░▀░▀     ▐█`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`█▌                                                                                    ▐█`)
-    fmt.Println(`████████████████████████████████████████████████████████████████████████████████████████`)
-}
-
-func PrintTunesdayRadioHeader() {
-    fmt.Println(`█████████████████████████████████████████████████████████████████████`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█▌     ░▀█▀░█░█░█▀█░█▀▀░█▀▀░█▀▄░█▀█░█░█░░░░█▀▄░█▀█░█▀▄░▀█▀░█▀█     ▐█`)
-    fmt.Println(`█▌     ░░█░░█░█░█░█░█▀▀░▀▀█░█░█░█▀█░░█░░░░░█▀▄░█▀█░█░█░░█░░█░█     ▐█`)
-    fmt.Println(`█▌     ░░▀░░▀▀▀░▀░▀░▀▀▀░▀▀▀░▀▀░░▀░▀░░▀░░▀░░▀░▀░▀░▀░▀▀░░▀▀▀░▀▀▀     ▐█`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█▌                                                                 ▐█`)
-    fmt.Println(`█████████████████████████████████████████████████████████████████████`)
+    printHeader(tunesdayHeader)
+    if sessionTheme != "" {
+        fmt.Fprintln(con.Out, "  " + i18n.T("Today's theme: %s", sessionTheme))
+    }
+    fmt.Fprintln(con.Out, "")
 }
+
+func PrintNotTunesdayHeader() { printHeader(notTunesdayHeader) }
+
+func PrintTunesdayRadioHeader() { printHeader(radioHeader) }
@@ -0,0 +1,280 @@
+package termui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Profile is how many colours the terminal can show.
+type Profile int
+
+const (
+	Plain     Profile = iota // no escape codes at all (TERM=dumb)
+	Mono                     // bold, underline and reverse but no colours (NO_COLOR)
+	ANSI16                   // the 16 basic colours
+	ANSI256                  // the xterm 256 colour palette
+	TrueColor                // 24-bit colour
+)
+
+var profileNames = map[string]Profile{
+	"none":      Plain,
+	"mono":      Mono,
+	"16":        ANSI16,
+	"256":       ANSI256,
+	"truecolor": TrueColor,
+}
+
+// DetectProfile works out the colour profile from the environment.
+// TUNESDAY_COLORS (none, mono, 16, 256 or truecolor) overrides the detection;
+// otherwise TERM=dumb and NO_COLOR switch colours off and COLORTERM and TERM
+// tell truecolor and 256 colour terminals apart.
+func DetectProfile(getenv func(string) string) Profile {
+	if p, ok := profileNames[strings.ToLower(getenv("TUNESDAY_COLORS"))]; ok {
+		return p
+	}
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return Plain
+	case getenv("NO_COLOR") != "":
+		return Mono
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	switch {
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "direct"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	}
+	return ANSI16
+}
+
+// Color is a terminal colour: one of the 16 basic colours, an entry of the
+// 256 colour palette or an RGB value. The zero Color is the default colour.
+type Color struct {
+	kind    colorKind
+	n       uint8
+	r, g, b uint8
+}
+
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota
+	colorBasic
+	colorIndex
+	colorRGB
+)
+
+var basicNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// basicRGB is the xterm rendering of the 16 basic colours, used to map
+// richer colours down.
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// ParseColor reads a colour name ("cyan", "bright-magenta"), a palette index
+// ("208") or a hex value ("#ff8800" or "#f80").
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range basicNames {
+		switch s {
+		case name:
+			return Color{kind: colorBasic, n: uint8(i)}, nil
+		case "bright-" + name:
+			return Color{kind: colorBasic, n: uint8(i + 8)}, nil
+		}
+	}
+	if s == "gray" || s == "grey" {
+		return Color{kind: colorBasic, n: 8}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n < 256 {
+		return Color{kind: colorIndex, n: uint8(n)}, nil
+	}
+	if hex := strings.TrimPrefix(s, "#"); hex != s {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return Color{kind: colorRGB, r: uint8(v >> 16), g: uint8(v >> 8), b: uint8(v)}, nil
+		}
+	}
+	return Color{}, fmt.Errorf("unknown colour %q", s)
+}
+
+func (c Color) rgb() (r, g, b uint8) {
+	switch c.kind {
+	case colorBasic:
+		v := basicRGB[c.n]
+		return v[0], v[1], v[2]
+	case colorIndex:
+		return indexRGB(c.n)
+	}
+	return c.r, c.g, c.b
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+func indexRGB(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		v := basicRGB[n]
+		return v[0], v[1], v[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	v := 8 + 10*(n-232)
+	return v, v, v
+}
+
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+// nearest returns the palette entry among the first n indexes closest to c.
+func (c Color) nearest(n int) uint8 {
+	r, g, b := c.rgb()
+	best, bestD := 0, -1
+	for i := 0; i < n; i++ {
+		ir, ig, ib := indexRGB(uint8(i))
+		if d := distance(r, g, b, ir, ig, ib); bestD < 0 || d < bestD {
+			best, bestD = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// sgr returns the SGR parameters selecting c as fore- or background colour
+// on a terminal with profile p, or "" when c or p has no colour.
+func (c Color) sgr(p Profile, bg bool) string {
+	if c.kind == colorDefault || p < ANSI16 {
+		return ""
+	}
+	base := 38
+	if bg {
+		base = 48
+	}
+	switch {
+	case p == TrueColor && c.kind == colorRGB:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, c.r, c.g, c.b)
+	case p >= ANSI256 && c.kind != colorBasic:
+		n := c.n
+		if c.kind == colorRGB {
+			n = c.nearest(256)
+		}
+		return fmt.Sprintf("%d;5;%d", base, n)
+	}
+	n := c.n
+	if c.kind != colorBasic {
+		n = c.nearest(16)
+	}
+	code := int(n) + base - 8
+	if n >= 8 {
+		code = int(n) - 8 + base + 52
+	}
+	return strconv.Itoa(code)
+}
+
+// Style is a text style: colours plus bold, underline and reverse video.
+type Style struct {
+	Fg, Bg    Color
+	Bold      bool
+	Underline bool
+	Reverse   bool
+}
+
+// ParseStyle reads a style such as "bold cyan", "reverse" or
+// "bold #ffffff on #0277bd". An empty spec is the plain style.
+func ParseStyle(spec string) (Style, error) {
+	var s Style
+	words := strings.Fields(spec)
+	for i := 0; i < len(words); i++ {
+		switch w := strings.ToLower(words[i]); w {
+		case "bold":
+			s.Bold = true
+		case "underline":
+			s.Underline = true
+		case "reverse":
+			s.Reverse = true
+		case "plain":
+		case "on":
+			if i+1 == len(words) {
+				return Style{}, fmt.Errorf("style %q: missing colour after \"on\"", spec)
+			}
+			i++
+			c, err := ParseColor(words[i])
+			if err != nil {
+				return Style{}, fmt.Errorf("style %q: %w", spec, err)
+			}
+			s.Bg = c
+		default:
+			c, err := ParseColor(w)
+			if err != nil {
+				return Style{}, fmt.Errorf("style %q: %w", spec, err)
+			}
+			s.Fg = c
+		}
+	}
+	return s, nil
+}
+
+// mustStyle is ParseStyle for the built-in skins.
+func mustStyle(spec string) Style {
+	s, err := ParseStyle(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// codes returns the escape sequence switching the style on for profile p.
+func (s Style) codes(p Profile) string {
+	if p == Plain {
+		return ""
+	}
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if s.Reverse {
+		params = append(params, "7")
+	}
+	if p == Mono && (s.Fg.kind != colorDefault || s.Bg.kind != colorDefault) && !s.Bold && !s.Underline && !s.Reverse {
+		// keep coloured highlights visible without colour
+		params = append(params, "1")
+	}
+	if fg := s.Fg.sgr(p, false); fg != "" {
+		params = append(params, fg)
+	}
+	if bg := s.Bg.sgr(p, true); bg != "" {
+		params = append(params, bg)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// Render returns text in the style for the console's colour profile.
+func (s Style) Render(text string) string {
+	return s.render(con.Colors, text)
+}
+
+func (s Style) render(p Profile, text string) string {
+	on := s.codes(p)
+	if on == "" || text == "" {
+		return text
+	}
+	return on + text + "\x1b[0m"
+}
@@ -0,0 +1,91 @@
+package termui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"atomicgo.dev/keyboard/keys"
+
+	"tunesday/internal/i18n"
+)
+
+// Console is what the screens run on: they draw to Out, read typed lines from
+// In and key presses from Keys. Clock, randomness and exiting are part of it
+// too, so that a scripted console can replay a whole flow in a test. Mouse
+// tells whether menus should ask the terminal to report clicks and the wheel
+// among the keys.
+type Console struct {
+	Out    io.Writer
+	In     io.Reader
+	Keys   func(onKey func(key keys.Key) (stop bool, err error)) error
+	Mouse  bool
+	Size   func() (width, height int)
+	Colors Profile
+	Now    func() time.Time
+	Sleep  func(time.Duration)
+	Rand   *rand.Rand
+	Exit   func(code int)
+}
+
+var (
+	con   *Console
+	input *bufio.Scanner
+)
+
+func init() { Use(Terminal()) }
+
+// Terminal returns the console on the process's own terminal.
+func Terminal() *Console {
+	return &Console{
+		Out:    os.Stdout,
+		In:     os.Stdin,
+		Keys:   listenKeys,
+		Mouse:  mouseReports,
+		Size:   terminalSize,
+		Colors: DetectProfile(os.Getenv),
+		Now:    time.Now,
+		Sleep:  time.Sleep,
+		Rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		Exit:   os.Exit,
+	}
+}
+
+// Use makes c the console of all screens.
+func Use(c *Console) {
+	con = c
+	input = bufio.NewScanner(c.In)
+}
+
+// Input returns the scanner for typed lines. Screens share it so that no
+// input is lost between them.
+func Input() *bufio.Scanner { return input }
+
+// Goodbye says goodbye on the console.
+func Goodbye() { fmt.Fprintln(con.Out, i18n.T("Goodbye!")) }
+
+// quit leaves the program after Ctrl-C.
+func quit() {
+	Goodbye()
+	con.Exit(0)
+}
+
+// terminalSize asks the terminal for its size, falling back to $COLUMNS and
+// $LINES and then to 80x24.
+func terminalSize() (width, height int) {
+	if w, h, ok := querySize(); ok {
+		return w, h
+	}
+	width, height = 80, 24
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 20 {
+		width = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 10 {
+		height = n
+	}
+	return width, height
+}
@@ -0,0 +1,146 @@
+package termui
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//go:embed fonts/blocks.flf
+var blocksFont string
+
+// bigFont draws the headers and the winner's name in big letters.
+var bigFont = mustFont(blocksFont)
+
+// deutschRunes are the optional characters a FIGlet font may define after
+// the printable ASCII ones, in this order.
+var deutschRunes = []rune{'Ä', 'Ö', 'Ü', 'ä', 'ö', 'ü', 'ß'}
+
+// Font is a FIGlet font. Only the full width layout is supported: characters
+// are set side by side without kerning or smushing.
+type Font struct {
+	height int
+	glyphs map[rune][]string
+}
+
+// parseFont reads a font in the FIGlet .flf format: the printable ASCII
+// characters and, if present, the Deutsch characters. Code-tagged characters
+// after them are ignored.
+func parseFont(src string) (*Font, error) {
+	sc := bufio.NewScanner(strings.NewReader(src))
+	if !sc.Scan() {
+		return nil, fmt.Errorf("font: empty")
+	}
+	header := strings.Fields(sc.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return nil, fmt.Errorf("font: not a FIGlet font")
+	}
+	hardblank := header[0][5:]
+	height, err := strconv.Atoi(header[1])
+	if err != nil || height < 1 {
+		return nil, fmt.Errorf("font: bad height %q", header[1])
+	}
+	comments, err := strconv.Atoi(header[5])
+	if err != nil || comments < 0 {
+		return nil, fmt.Errorf("font: bad comment line count %q", header[5])
+	}
+	for i := 0; i < comments; i++ {
+		if !sc.Scan() {
+			return nil, fmt.Errorf("font: truncated comment")
+		}
+	}
+
+	f := &Font{height: height, glyphs: make(map[rune][]string)}
+	codes := make([]rune, 0, 95+len(deutschRunes))
+	for r := rune(' '); r <= '~'; r++ {
+		codes = append(codes, r)
+	}
+	codes = append(codes, deutschRunes...)
+	for _, r := range codes {
+		rows := make([]string, height)
+		for i := range rows {
+			if !sc.Scan() {
+				if i == 0 && r > '~' {
+					return f, nil // the Deutsch characters are optional
+				}
+				return nil, fmt.Errorf("font: truncated at %q", r)
+			}
+			line := strings.TrimRight(sc.Text(), " \r")
+			if line == "" {
+				return nil, fmt.Errorf("font: empty line in %q", r)
+			}
+			end := line[len(line)-1:]
+			rows[i] = strings.ReplaceAll(strings.TrimRight(line, end), hardblank, " ")
+		}
+		w := len([]rune(rows[0]))
+		for _, row := range rows[1:] {
+			if len([]rune(row)) != w {
+				return nil, fmt.Errorf("font: ragged character %q", r)
+			}
+		}
+		f.glyphs[r] = rows
+	}
+	return f, sc.Err()
+}
+
+func mustFont(src string) *Font {
+	f, err := parseFont(src)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (f *Font) glyph(r rune) ([]string, bool) {
+	if g, ok := f.glyphs[r]; ok {
+		return g, true
+	}
+	g, ok := f.glyphs[unicode.ToUpper(r)]
+	return g, ok
+}
+
+// Has reports whether the font can draw every character of s.
+func (f *Font) Has(s string) bool {
+	for _, r := range s {
+		if _, ok := f.glyph(r); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Width returns how many columns s is wide in the font.
+func (f *Font) Width(s string) int {
+	w := 0
+	for _, r := range s {
+		if g, ok := f.glyph(r); ok {
+			w += len([]rune(g[0]))
+		} else if g, ok := f.glyph('?'); ok {
+			w += len([]rune(g[0]))
+		}
+	}
+	return w
+}
+
+// Render draws s in the font, one string per row. Characters the font does
+// not have are drawn as '?'.
+func (f *Font) Render(s string) []string {
+	rows := make([]strings.Builder, f.height)
+	for _, r := range s {
+		g, ok := f.glyph(r)
+		if !ok {
+			g, _ = f.glyph('?')
+		}
+		for i := range g {
+			rows[i].WriteString(g[i])
+		}
+	}
+	out := make([]string, f.height)
+	for i := range rows {
+		out[i] = rows[i].String()
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package termui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFont(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("flf2a$ 2 2 4 -1 1\nA tiny font.\n")
+	for r := ' '; r <= '~'; r++ {
+		c, end := string(r), "@"
+		if r == ' ' {
+			c = "$"
+		}
+		if r == '@' {
+			end = "#" // any character can end the lines
+		}
+		b.WriteString(c + c + end + "\n" + c + "$" + end + end + "\n")
+	}
+	f, err := parseFont(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Render("Hi!"); len(got) != 2 || got[0] != "HHii!!" || got[1] != "H i ! " {
+		t.Fatalf("Render = %q", got)
+	}
+	if f.Has("Hü") || !f.Has("Hi") || f.Width("Hi") != 4 {
+		t.Fatalf("Has/Width wrong")
+	}
+	for _, bad := range []string{"", "figlet 2 2 4 -1 0\n", "flf2a$ x 2 4 -1 0\n", "flf2a$ 2 2 4 -1 0\nAA@\nA@@\n"} {
+		if _, err := parseFont(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestBigFont(t *testing.T) {
+	if !bigFont.Has("Jürgen & Bob?") || bigFont.Has("Zoë") {
+		t.Fatal("the font should have ü but not ë")
+	}
+	got := strings.Join(bigFont.Render("Tunesday"), "\n")
+	want := "░▀█▀░█░█░█▀█░█▀▀░█▀▀░█▀▄░█▀█░█░█\n" +
+		"░░█░░█░█░█░█░█▀▀░▀▀█░█░█░█▀█░░█░\n" +
+		"░░▀░░▀▀▀░▀░▀░▀▀▀░▀▀▀░▀▀░░▀░▀░░▀░"
+	if got != want {
+		t.Fatalf("Render =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHeadersFitWidth(t *testing.T) {
+	for _, h := range []header{tunesdayHeader, notTunesdayHeader, radioHeader} {
+		for w := 10; w <= 140; w++ {
+			lines := bigBanner(h, w)
+			for _, l := range lines {
+				if n := len([]rune(l)); n > w {
+					t.Fatalf("%s at width %d: line is %d wide: %q", h.small, w, n, l)
+				}
+			}
+		}
+	}
+	if got := len(bigBanner(tunesdayHeader, 80)); got != 14 {
+		t.Errorf("classic header at 80 columns has %d lines, want 14", got)
+	}
+	if got := bigBanner(tunesdayHeader, 50); len(got) != 13 || !strings.Contains(got[5], "█▀█") {
+		t.Errorf("header at 50 columns should wrap onto three lines of big letters:\n%s", strings.Join(got, "\n"))
+	}
+	if got := bigBanner(tunesdayHeader, 30); len(got) != 3 || !strings.Contains(got[1], "It's Tunesday!") {
+		t.Errorf("header at 30 columns should be compact:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestDrawBigWinnerName(t *testing.T) {
+	out := run(t, Script{Width: 80}, func() { DrawBigWinner("Ann", "") })
+	if !strings.Contains(out, "█▀█ █▀█ █▀█") || !strings.Contains(out, "is today's tune provider!!") {
+		t.Errorf("name not drawn in big letters:\n%s", out)
+	}
+	out = run(t, Script{Width: 80}, func() { DrawBigWinner("Zoë", "") })
+	if !strings.Contains(out, "Zoë is today's tune provider!!") {
+		t.Errorf("name the font lacks should be plain text:\n%s", out)
+	}
+}
@@ -0,0 +1,161 @@
+package termui
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"atomicgo.dev/keyboard/keys"
+)
+
+// Mouse reports arrive among the key presses as keys with the code mouseKey.
+// Their runes hold the button, the column and the row, both counted from 1.
+const mouseKey keys.KeyCode = -1000
+
+// Buttons of mouse reports as numbered by the terminal.
+const (
+	mouseLeft      = 0
+	mouseWheelUp   = 64
+	mouseWheelDown = 65
+)
+
+// Escape sequences that switch SGR mouse reporting of clicks and the wheel
+// on and off.
+const (
+	mouseOn  = "\x1b[?1000h\x1b[?1006h"
+	mouseOff = "\x1b[?1006l\x1b[?1000l"
+)
+
+// mouseEvent returns the button, column and row of a mouse report.
+func mouseEvent(k keys.Key) (button, x, y int, ok bool) {
+	if k.Code != mouseKey || len(k.Runes) != 3 {
+		return 0, 0, 0, false
+	}
+	return int(k.Runes[0]), int(k.Runes[1]), int(k.Runes[2]), true
+}
+
+func mouse(button, x, y int) keys.Key {
+	return keys.Key{Code: mouseKey, Runes: []rune{rune(button), rune(x), rune(y)}}
+}
+
+// Click returns a left click at column x and row y, both counted from 1.
+func Click(x, y int) []keys.Key { return []keys.Key{mouse(mouseLeft, x, y)} }
+
+// Wheel returns n turns of the mouse wheel, down for positive n and up for
+// negative n.
+func Wheel(n int) []keys.Key {
+	button := mouseWheelDown
+	if n < 0 {
+		button, n = mouseWheelUp, -n
+	}
+	out := make([]keys.Key, n)
+	for i := range out {
+		out[i] = mouse(button, 1, 1)
+	}
+	return out
+}
+
+// sequences are the escape sequences of the keys the screens handle.
+var sequences = map[string]keys.KeyCode{
+	"\x1b[A": keys.Up, "\x1bOA": keys.Up,
+	"\x1b[B": keys.Down, "\x1bOB": keys.Down,
+	"\x1b[C": keys.Right, "\x1bOC": keys.Right,
+	"\x1b[D": keys.Left, "\x1bOD": keys.Left,
+	"\x1b[H": keys.Home, "\x1bOH": keys.Home, "\x1b[1~": keys.Home, "\x1b[7~": keys.Home,
+	"\x1b[F": keys.End, "\x1bOF": keys.End, "\x1b[4~": keys.End, "\x1b[8~": keys.End,
+	"\x1b[5~": keys.PgUp,
+	"\x1b[6~": keys.PgDown,
+	"\x1b[3~": keys.Delete,
+	"\x1b[Z":  keys.ShiftTab,
+}
+
+// decodeKeys splits what the terminal sent in one read into key presses and
+// mouse reports. Mouse releases and unknown escape sequences are dropped.
+func decodeKeys(b []byte) []keys.Key {
+	var out []keys.Key
+	for len(b) > 0 {
+		if b[0] != 0x1b {
+			r, n := utf8.DecodeRune(b)
+			b = b[n:]
+			switch {
+			case r == ' ':
+				out = append(out, keys.Key{Code: keys.Space, Runes: []rune{r}})
+			case r == 0x7f || r == 0x08:
+				out = append(out, keys.Key{Code: keys.Backspace})
+			case r < 0x20:
+				out = append(out, keys.Key{Code: keys.KeyCode(r)})
+			case r != utf8.RuneError:
+				out = append(out, keys.Key{Code: keys.RuneKey, Runes: []rune{r}})
+			}
+			continue
+		}
+
+		seq := escapeSequence(b)
+		b = b[len(seq):]
+		switch {
+		case seq == "\x1b":
+			out = append(out, keys.Key{Code: keys.Esc})
+		case strings.HasPrefix(seq, "\x1b[<"):
+			if k, ok := parseMouse(seq); ok {
+				out = append(out, k)
+			}
+		case sequences[seq] != 0:
+			out = append(out, keys.Key{Code: sequences[seq]})
+		case len(seq) > 1 && seq[1] != '[' && seq[1] != 'O':
+			// Alt and a key
+			ks := decodeKeys([]byte(seq[1:]))
+			for i := range ks {
+				ks[i].AltPressed = true
+			}
+			out = append(out, ks...)
+		}
+	}
+	return out
+}
+
+// escapeSequence returns the escape sequence b starts with: a CSI or SS3
+// sequence up to its final byte, Esc and one rune for Alt, or a lone Esc.
+func escapeSequence(b []byte) string {
+	if len(b) < 2 {
+		return string(b)
+	}
+	switch b[1] {
+	case '[':
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return string(b[:i+1])
+			}
+		}
+		return string(b)
+	case 'O':
+		if len(b) > 2 {
+			return string(b[:3])
+		}
+		return string(b)
+	case 0x1b:
+		return "\x1b"
+	}
+	_, n := utf8.DecodeRune(b[1:])
+	return string(b[:1+n])
+}
+
+// parseMouse parses an SGR mouse report, "\x1b[<button;x;yM" for presses and
+// "...m" for releases. Only presses are returned.
+func parseMouse(seq string) (keys.Key, bool) {
+	if !strings.HasSuffix(seq, "M") {
+		return keys.Key{}, false
+	}
+	fields := strings.Split(seq[3:len(seq)-1], ";")
+	if len(fields) != 3 {
+		return keys.Key{}, false
+	}
+	var n [3]int
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return keys.Key{}, false
+		}
+		n[i] = v
+	}
+	return mouse(n[0], n[1], n[2]), true
+}
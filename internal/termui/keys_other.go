@@ -0,0 +1,15 @@
+//go:build !unix
+
+package termui
+
+import "atomicgo.dev/keyboard"
+
+// mouseReports tells whether listenKeys passes on mouse reports; the keyboard
+// package doesn't.
+const mouseReports = false
+
+// listenKeys leaves the keys to the keyboard package.
+var listenKeys = keyboard.Listen
+
+// hideInput can't hide what is typed here.
+func hideInput() (restore func(), ok bool) { return nil, false }
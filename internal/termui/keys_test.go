@@ -0,0 +1,28 @@
+package termui
+
+import (
+	"reflect"
+	"testing"
+
+	"atomicgo.dev/keyboard/keys"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	for in, want := range map[string][]keys.Key{
+		"\x1b[A\x1b[B":         Press("up", "down"),
+		"\x1b[5~\x1b[6~\x1bOH": Press("pgup", "pgdown", "home"),
+		"\x1b":                 Press("esc"),
+		"\r\x7f\x03":           Press("enter", "backspace", "ctrl+c"),
+		"jö ":                  Type("jö "),
+		"\x1bx":                {{Code: keys.RuneKey, Runes: []rune{'x'}, AltPressed: true}},
+		"\x1b[<0;12;7M":        Click(12, 7),
+		"\x1b[<0;12;7m":        nil,
+		"\x1b[<65;1;1M\x1b[A":  append(Wheel(1), Press("up")...),
+		"\x1b[<64;3;4M":        {mouse(mouseWheelUp, 3, 4)},
+		"\x1b[99~x":            Type("x"),
+	} {
+		if got := decodeKeys([]byte(in)); !reflect.DeepEqual(got, want) {
+			t.Errorf("decodeKeys(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
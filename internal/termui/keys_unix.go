@@ -0,0 +1,56 @@
+//go:build unix
+
+package termui
+
+import (
+	"os"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/containerd/console"
+)
+
+// mouseReports tells whether listenKeys passes on mouse reports.
+const mouseReports = true
+
+// listenKeys calls onKey for every key press and mouse report on the
+// terminal until it returns stop, keeping the terminal in raw mode meanwhile.
+// Without a terminal it leaves the keys to the keyboard package.
+func listenKeys(onKey func(key keys.Key) (stop bool, err error)) error {
+	c, err := console.ConsoleFromFile(os.Stdin)
+	if err != nil {
+		return keyboard.Listen(onKey)
+	}
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return keyboard.Listen(onKey)
+	}
+	defer tty.Close()
+	if err := c.SetRaw(); err != nil {
+		return err
+	}
+	defer c.Reset()
+
+	buf := make([]byte, 256)
+	for {
+		n, err := tty.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range decodeKeys(buf[:n]) {
+			if stop, err := onKey(k); stop || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hideInput stops the terminal from echoing what is typed until restore is
+// called; ok is false without a terminal.
+func hideInput() (restore func(), ok bool) {
+	c, err := console.ConsoleFromFile(os.Stdin)
+	if err != nil || c.DisableEcho() != nil {
+		return nil, false
+	}
+	return func() { c.Reset() }, true
+}
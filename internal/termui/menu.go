@@ -3,64 +3,50 @@ package termui
 import (
     "context"
     "fmt"
+    "strings"
 
-    "atomicgo.dev/keyboard"
     "atomicgo.dev/keyboard/keys"
+
+    "tunesday/internal/i18n"
 )
 
 // ShowMenu returns the chosen index or -1 when the user pressed Ctrl-C and -2 on Esc.
+//
+// Besides the arrow keys the menu takes j/k, g/G for the first and last item,
+// PageUp/PageDown, 1-9 to pick one of the first nine items, clicks and the
+// mouse wheel. Typing any other letter, or /, filters the items.
 func ShowMenu(ctx context.Context, title string, items []string) int {
-    selected := 0
+    return showMenu(ctx, title, items, nil)
+}
+
+// showMenu is ShowMenu with an optional banner drawn between header and title.
+// The banner returns the number of lines it printed. Menus taller than the
+// terminal scroll.
+func showMenu(ctx context.Context, title string, items []string, banner func() int) int {
+    m := &menu{items: items}
+    m.refilter(0)
     finished := make(chan int, 1)
 
     // first draw
-    ClearScreen()
-    PrintTunesdayHeader()
-    if title != "" {
-        fmt.Println(title)
-    }
-    for i, item := range items {
-        cursor := "  "
-        if i == selected {
-            cursor = "▶ "
-        }
-        fmt.Printf("%s%s\n", cursor, item)
+    m.draw(title, banner)
+    stop := onResize(func() { m.draw(title, banner) })
+    defer stop()
+    if con.Mouse {
+        fmt.Fprint(con.Out, mouseOn)
+        defer fmt.Fprint(con.Out, mouseOff)
     }
 
-    _ = keyboard.Listen(func(key keys.Key) (bool, error) {
-        switch key.Code {
-        case keys.Up:
-            if selected > 0 {
-                selected--
-            }
-        case keys.Down:
-            if selected < len(items)-1 {
-                selected++
-            }
-        case keys.Enter:
-            finished <- selected
-            return true, nil
-        case keys.CtrlC:
-            finished <- -1
-            return true, nil
-        case keys.Esc:
-            finished <- -2
+    _ = con.Keys(func(key keys.Key) (bool, error) {
+        drawMu.Lock()
+        defer drawMu.Unlock()
+
+        if idx, done := m.handle(key); done {
+            finished <- idx
             return true, nil
         }
 
         // redraw
-        ClearScreen()
-        PrintTunesdayHeader()
-        if title != "" {
-            fmt.Println(title)
-        }
-        for i, item := range items {
-            cursor := "  "
-            if i == selected {
-                cursor = "▶ "
-            }
-            fmt.Printf("%s%s\n", cursor, item)
-        }
+        m.draw(title, banner)
         return false, nil
     })
 
@@ -71,3 +57,254 @@ func ShowMenu(ctx context.Context, title string, items []string) int {
         return idx
     }
 }
+
+// menu is the state of a menu on screen. selected and top are positions in
+// shown, the indexes of the items matching the filter.
+type menu struct {
+    items     []string
+    shown     []int
+    selected  int
+    top       int
+    filtering bool
+    query     string
+    itemsRow  int // screen row of the first visible item, counted from 1
+    visible   int // number of items drawn
+}
+
+// refilter recomputes the shown items for the query and keeps item keep
+// selected if it is still shown.
+func (m *menu) refilter(keep int) {
+    q := strings.ToLower(m.query)
+    m.shown = m.shown[:0]
+    m.selected = 0
+    for i, item := range m.items {
+        if strings.Contains(strings.ToLower(item), q) {
+            if i == keep {
+                m.selected = len(m.shown)
+            }
+            m.shown = append(m.shown, i)
+        }
+    }
+}
+
+// current returns the selected item's index in items, or -1 if no item
+// matches the filter.
+func (m *menu) current() int {
+    if len(m.shown) == 0 {
+        return -1
+    }
+    return m.shown[m.selected]
+}
+
+// move moves the selection by n items, wrapping around at either end when
+// wrap is set and stopping there otherwise.
+func (m *menu) move(n int, wrap bool) {
+    count := len(m.shown)
+    if count == 0 {
+        return
+    }
+    m.selected += n
+    switch {
+    case wrap:
+        m.selected = ((m.selected % count) + count) % count
+    case m.selected < 0:
+        m.selected = 0
+    case m.selected > count-1:
+        m.selected = count - 1
+    }
+}
+
+// setQuery changes the filter, keeping the selected item if it still matches.
+func (m *menu) setQuery(q string) {
+    keep := m.current()
+    m.query = q
+    m.refilter(keep)
+}
+
+// handle acts on a key press. It returns the result of the menu and true
+// when the menu is done.
+func (m *menu) handle(key keys.Key) (int, bool) {
+    switch key.Code {
+    case keys.Up:
+        m.move(-1, true)
+    case keys.Down:
+        m.move(1, true)
+    case keys.PgUp:
+        m.move(-termHeight()/2, false)
+    case keys.PgDown:
+        m.move(termHeight()/2, false)
+    case keys.Home:
+        m.move(-len(m.shown), false)
+    case keys.End:
+        m.move(len(m.shown), false)
+    case keys.Enter:
+        if idx := m.current(); idx >= 0 {
+            return idx, true
+        }
+    case keys.CtrlC:
+        return -1, true
+    case keys.Esc:
+        if !m.filtering {
+            return -2, true
+        }
+        m.filtering = false
+        m.setQuery("")
+    case keys.Backspace, keys.CtrlH:
+        if q := []rune(m.query); len(q) > 0 {
+            m.setQuery(string(q[:len(q)-1]))
+        } else {
+            m.filtering = false
+        }
+    case keys.CtrlU:
+        m.setQuery("")
+    case keys.Space:
+        if m.filtering {
+            m.setQuery(m.query + " ")
+        }
+    case keys.RuneKey:
+        if m.filtering {
+            m.setQuery(m.query + string(key.Runes))
+            break
+        }
+        return m.shortcut(key.Runes)
+    case mouseKey:
+        return m.mouse(key)
+    }
+    return 0, false
+}
+
+// shortcut handles the keys that aren't typed into the filter.
+func (m *menu) shortcut(runes []rune) (int, bool) {
+    if len(runes) != 1 {
+        m.filtering = true
+        m.setQuery(string(runes))
+        return 0, false
+    }
+    switch r := runes[0]; {
+    case r == 'j':
+        m.move(1, true)
+    case r == 'k':
+        m.move(-1, true)
+    case r == 'g':
+        m.move(-len(m.shown), false)
+    case r == 'G':
+        m.move(len(m.shown), false)
+    case r >= '1' && r <= '9':
+        if n := int(r - '1'); n < len(m.shown) {
+            return m.shown[n], true
+        }
+    case r == '/':
+        m.filtering = true
+    default:
+        m.filtering = true
+        m.setQuery(string(r))
+    }
+    return 0, false
+}
+
+// mouse picks the clicked item and scrolls with the wheel.
+func (m *menu) mouse(key keys.Key) (int, bool) {
+    button, _, y, ok := mouseEvent(key)
+    if !ok {
+        return 0, false
+    }
+    switch button {
+    case mouseWheelUp:
+        m.move(-1, false)
+    case mouseWheelDown:
+        m.move(1, false)
+    case mouseLeft:
+        if row := y - m.itemsRow; row >= 0 && row < m.visible {
+            return m.shown[m.top+row], true
+        }
+    }
+    return 0, false
+}
+
+func (m *menu) draw(title string, banner func() int) {
+    ClearScreen()
+    PrintTunesdayHeader()
+    used := headerHeight()
+    if banner != nil {
+        used += banner()
+    }
+    if title != "" {
+        fmt.Fprintln(con.Out, title)
+        used += lineCount(title)
+    }
+    if m.filtering {
+        fmt.Fprintln(con.Out, i18n.T("Filter: %s", m.query+inputCursor()))
+        used++
+    }
+
+    // room for the items, keeping a line for the key help and the last line
+    // free for the cursor, and lines for the scroll markers when the items
+    // don't fit
+    room := termHeight() - used - 2
+    if len(m.shown) > room {
+        room -= 2
+    }
+    if room < 3 {
+        room = 3
+    }
+    m.top = scrollWindow(m.selected, m.top, room, len(m.shown))
+    end := m.top + room
+    if end > len(m.shown) {
+        end = len(m.shown)
+    }
+
+    if m.top > 0 {
+        fmt.Fprintln(con.Out, skin.Muted.Render("  "+i18n.T("↑ %d more", m.top)))
+        used++
+    }
+    m.itemsRow = used + 1
+    m.visible = end - m.top
+    if len(m.shown) == 0 {
+        fmt.Fprintln(con.Out, "  "+i18n.T("No matches."))
+    }
+    for i := m.top; i < end; i++ {
+        label := "  "
+        if !m.filtering && i < 9 {
+            label = fmt.Sprintf("%d ", i+1)
+        }
+        item := TruncateRunes(m.items[m.shown[i]], termWidth()-4)
+        fmt.Fprintln(con.Out, cursorLine(label+item, i == m.selected))
+    }
+    if end < len(m.shown) {
+        fmt.Fprintln(con.Out, skin.Muted.Render("  "+i18n.T("↓ %d more", len(m.shown)-end)))
+    }
+
+    help := i18n.T("↑↓ jk move · g G ends · 1-9 pick · / filter · Enter select · Esc back")
+    if m.filtering {
+        help = i18n.T("↑↓ move · Enter select · Backspace edit · Esc clear filter")
+    }
+    fmt.Fprintln(con.Out, skin.Muted.Render("  "+TruncateRunes(help, termWidth()-2)))
+}
+
+// scrollWindow returns the first visible row so that selected stays within a
+// window of room rows over n rows.
+func scrollWindow(selected, top, room, n int) int {
+    if selected < top {
+        top = selected
+    }
+    if selected >= top+room {
+        top = selected - room + 1
+    }
+    if top > n-room {
+        top = n - room
+    }
+    if top < 0 {
+        top = 0
+    }
+    return top
+}
+
+// lineCount returns how many terminal lines s takes when wrapped.
+func lineCount(s string) int {
+    w := termWidth()
+    n := len([]rune(s))
+    if n == 0 || w <= 0 {
+        return 1
+    }
+    return (n + w - 1) / w
+}
@@ -0,0 +1,47 @@
+package termui
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// drawMu serializes screen updates between key handlers and resize redraws.
+var drawMu sync.Mutex
+
+// onResize calls redraw, holding drawMu, whenever the terminal is resized,
+// until the returned stop function is called.
+func onResize(redraw func()) (stop func()) {
+	sig := make(chan os.Signal, 1)
+	notifyResize(sig)
+	stopWatch := watch(sig, redraw)
+	return func() {
+		signal.Stop(sig)
+		stopWatch()
+	}
+}
+
+// watch calls redraw, holding drawMu, for every signal on sig. The returned
+// stop function waits for a redraw in progress, so that nothing of the old
+// screen is painted once it returns.
+func watch(sig <-chan os.Signal, redraw func()) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-sig:
+				drawMu.Lock()
+				redraw()
+				drawMu.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
@@ -0,0 +1,25 @@
+package termui
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStopWaitsForRedraw(t *testing.T) {
+	sig := make(chan os.Signal, 1)
+	started := make(chan struct{})
+	var painted atomic.Bool
+	stop := watch(sig, func() {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		painted.Store(true)
+	})
+	sig <- os.Interrupt
+	<-started
+	stop()
+	if !painted.Load() {
+		t.Error("stop returned while a redraw was still painting")
+	}
+}
@@ -3,114 +3,254 @@ package termui
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"math/rand"
-	"os"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"tunesday/internal/core"
+	"tunesday/internal/i18n"
 	"tunesday/internal/playlist"
 )
 
-func SelectProvider(ctx context.Context, data *core.Data) string {
+// PickTeam asks whose Tunesday to open. It returns "" when the user leaves.
+func PickTeam(ctx context.Context, teams []string) string {
+	idx := ShowMenu(ctx, i18n.T("Which team?"), teams)
+	if idx < 0 {
+		return ""
+	}
+	return teams[idx]
+}
+
+// SelectProvider draws today's providers among the active participants and
+// records the draws in today's session. Participants who owe a tune from an
+// earlier pass are drawn first. After the draw the team can re-roll, hand over
+// to a volunteer or let a winner pass; every choice is kept in the history.
+// Draws still pending from earlier today come back instead of a new draw.
+// It returns the draws that should deliver a tune.
+func SelectProvider(ctx context.Context, data *core.Data) []*core.Draw {
 	ClearScreen()
 	PrintTunesdayHeader()
 
 	if len(data.Participants) == 0 {
-		fmt.Println("No participants available.")
-		return ""
+		fmt.Fprintln(con.Out, i18n.T("No participants available."))
+		return nil
 	}
 
-	active := make([]string, 0, len(data.Participants))
-	for name := range data.Participants {
-		if data.Disabled != nil && data.Disabled[name] {
-			continue
-		}
-		active = append(active, name)
-	}
+	active := data.ActiveParticipants()
 	if len(active) == 0 {
-		fmt.Println("All participants are deactivated. Activate at least one to select a provider.")
+		fmt.Fprintln(con.Out, i18n.T("All participants are deactivated. Activate at least one to select a provider."))
 		PressEnterToContinue()
-		return ""
+		return nil
 	}
 
-	names := append([]string(nil), active...)
-	sort.Strings(names)
-	winner := names[rand.Intn(len(names))]
+	var picked []*core.Draw
+	_ = rememberIf(data, func() error {
+		picked = runDraw(ctx, data, active)
+		return nil
+	}, "Draw today's provider")
+	return picked
+}
 
-	dur := time.Duration(1500+rand.Intn(1501)) * time.Millisecond
-	endAt := time.Now().Add(dur)
-	for time.Now().Before(endAt) {
-		ClearScreen()
-		PrintTunesdayHeader()
-		fmt.Println("Selecting today's provider…")
-		hi := rand.Intn(len(names))
-		drawNameList(names, hi)
-		time.Sleep(time.Duration(40+rand.Intn(61)) * time.Millisecond)
+// runDraw runs SelectProvider's draw over the active participants.
+func runDraw(ctx context.Context, data *core.Data, active []*core.Participant) []*core.Draw {
+	session := data.SessionOn(con.Now())
+	excluded := make(map[string]bool)
+	candidates := func() []*core.Participant {
+		var ps []*core.Participant
+		for _, p := range active {
+			if !excluded[p.ID] {
+				ps = append(ps, p)
+			}
+		}
+		return ps
 	}
 
-	ClearScreen()
-	PrintTunesdayHeader()
-	fmt.Println("Selecting today's provider…")
-	winnerIdx := 0
-	for i, n := range names {
-		if n == winner {
-			winnerIdx = i
-			break
+	// draws left pending today, by backing out of this menu, are taken up
+	// again rather than drawn anew, which would count twice
+	var resume []*core.Draw
+	for _, dr := range session.Draws {
+		if dr.Outcome == core.OutcomePending {
+			resume = append(resume, dr)
+		}
+	}
+	var queue []*core.Participant
+	counts := data.Counts()
+	if len(resume) > 0 {
+		for _, dr := range session.Draws {
+			excluded[dr.ParticipantID] = true
+		}
+	} else {
+		queue = drawProviders(candidates(), session.ProviderSlots(), counts)
+	}
+	var picked []*core.Draw
+	for len(resume) > 0 || len(queue) > 0 {
+		var winner *core.Participant
+		var draw *core.Draw
+		if len(resume) > 0 {
+			draw, resume = resume[0], resume[1:]
+			if winner = data.Participant(draw.ParticipantID); winner == nil {
+				continue
+			}
+		} else {
+			winner, queue = queue[0], queue[1:]
+			excluded[winner.ID] = true
+			for _, p := range queue {
+				excluded[p.ID] = true
+			}
+			draw = session.AddDraw(winner.ID, con.Now())
+		}
+
+	decide:
+		for draw.Outcome == core.OutcomePending {
+			title := i18n.T("What now?")
+			if n := session.ProviderSlots(); n > 1 {
+				title = i18n.T("Provider %d of %d. What now?", len(picked)+1, n)
+			}
+			if counts[winner.ID].Owes > 0 {
+				title = i18n.T("%s owes a tune from an earlier Tunesday. %s", winner.Name, title)
+			}
+			idx := showMenu(ctx, title, []string{
+				i18n.T("Add the tune"),
+				i18n.T("Re-roll (excluding %s)", winner.Name),
+				i18n.T("Swap with volunteer"),
+				i18n.T("%s passes, owes next week", winner.Name),
+			}, func() int { return DrawBigWinner(winner.Name, session.Theme) })
+
+			switch idx {
+			case -1:
+				quit()
+			case -2: // back out, leaving the draw pending
+				return picked
+			case 0: // Add the tune
+				picked = append(picked, draw)
+				break decide
+			case 1, 3: // Re-roll or pass
+				if idx == 1 {
+					draw.Outcome = core.OutcomeSkipped
+				} else {
+					draw.Outcome = core.OutcomePassed
+				}
+				if next := candidates(); len(next) > 0 {
+					queue = append(drawProviders(next, 1, counts), queue...)
+				} else {
+					fmt.Fprintln(con.Out, i18n.T("Nobody left to draw today."))
+					PressEnterToContinue()
+				}
+			case 2: // Swap
+				// whoever is drawn already, for this slot or a later one,
+				// can't volunteer
+				others := candidates()
+				volunteer := pickParticipant(ctx, i18n.T("Who volunteers instead of %s?", winner.Name), others)
+				if volunteer == nil {
+					continue
+				}
+				excluded[volunteer.ID] = true
+				picked = append(picked, session.Swap(draw, volunteer.ID, con.Now()))
+			}
 		}
 	}
-	drawNameList(names, winnerIdx)
-	time.Sleep(1200 * time.Millisecond)
+	return picked
+}
+
+// drawProviders animates the draw over the candidates and returns n distinct
+// winners. Candidates who owe a tune are picked before everyone else.
+func drawProviders(candidates []*core.Participant, n int, counts map[string]core.Counts) []*core.Participant {
+	winnerIdx := core.PickProviders(candidates, n, counts, con.Rand.Shuffle)
+	n = len(winnerIdx)
+
+	names := make([]string, len(candidates))
+	for i, p := range candidates {
+		names[i] = p.Name
+	}
+	title := i18n.T("Selecting today's provider…")
+	if n > 1 {
+		title = i18n.T("Selecting today's %d providers…", n)
+	}
+
+	spin(title, names, winnerIdx)
+
+	winners := make([]*core.Participant, n)
+	for i, idx := range winnerIdx {
+		winners[i] = candidates[idx]
+	}
+	return winners
+}
+
+// spin animates a roulette over names and settles on the final highlights.
+func spin(title string, names []string, final []int) {
+	dur := time.Duration(1500+con.Rand.Intn(1501)) * time.Millisecond
+	endAt := con.Now().Add(dur)
+	for con.Now().Before(endAt) {
+		ClearScreen()
+		PrintTunesdayHeader()
+		fmt.Fprintln(con.Out, title)
+		drawNameList(names, con.Rand.Perm(len(names))[:len(final)]...)
+		con.Sleep(time.Duration(40+con.Rand.Intn(61)) * time.Millisecond)
+	}
 
 	ClearScreen()
 	PrintTunesdayHeader()
-	DrawBigWinner(winner)
-	if data.Participants != nil {
-		data.Participants[winner]++
-	}
-	return winner
+	fmt.Fprintln(con.Out, title)
+	drawNameList(names, final...)
+	con.Sleep(1200 * time.Millisecond)
 }
 
 // removed: RemoveYouTubeTracker moved to playlist.StripTrackingParams
 
-func AddTuneWithProvider(ctx context.Context, data *core.Data, scanner *bufio.Scanner, providerName string, yt playlist.TitleProvider) {
+// AddTuneWithProvider asks the drawn provider for their link. Cancelling marks
+// the draw as skipped; a failed lookup leaves it pending.
+func AddTuneWithProvider(ctx context.Context, data *core.Data, scanner *bufio.Scanner, draw *core.Draw, yt playlist.TitleProvider) {
 	ClearScreen()
 	PrintTunesdayHeader()
-	fmt.Printf("Today's tune provider is: %s\n\n", providerName)
-	fmt.Println("Paste the tune link (YouTube https://…) or press Enter to cancel:")
-	fmt.Print("> ")
+	fmt.Fprintln(con.Out, i18n.T("Today's tune provider is: %s", data.ParticipantName(draw.ParticipantID)))
+	fmt.Fprintln(con.Out, "")
+	fmt.Fprintln(con.Out, i18n.T("Paste the tune link (YouTube https://…) or press Enter to cancel:"))
+	fmt.Fprint(con.Out, "> ")
 	if !scanner.Scan() {
 		return
 	}
 	raw := strings.TrimSpace(scanner.Text())
 	if raw == "" {
+		draw.Outcome = core.OutcomeSkipped
+		fmt.Fprintln(con.Out, i18n.T("Skipped."))
 		return
 	}
 	raw = playlist.StripTrackingParams(raw)
 
 	id, ok := yt.NormalizeYouTubeID(raw)
 	if !ok {
-		fmt.Println("Only https:// YouTube links are supported for automatic title fetch.")
+		fmt.Fprintln(con.Out, i18n.T("Only https:// YouTube links are supported for automatic title fetch."))
 		return
 	}
-	title, err := yt.FetchTitle(ctx, id)
-	if err != nil {
-		fmt.Println("Failed to fetch title:", err)
-		return
+	t := core.Tune{Link: raw, ID: id, Provider: "youtube", AddedAt: con.Now()}
+	if ip, ok := yt.(playlist.InfoProvider); ok {
+		info, err := ip.FetchInfo(ctx, id)
+		if err != nil {
+			fmt.Fprintln(con.Out, i18n.T("Failed to fetch title: %v", err))
+			return
+		}
+		t.Name, t.Channel, t.Seconds = info.Title, info.Channel, int(info.Duration.Seconds())
+	} else {
+		title, err := yt.FetchTitle(ctx, id)
+		if err != nil {
+			fmt.Fprintln(con.Out, i18n.T("Failed to fetch title: %v", err))
+			return
+		}
+		t.Name = title
 	}
-	t := core.Tune{Name: title, Link: raw, ID: id, Provider: "youtube", AddedAt: time.Now()}
-	data.Tunes = append(data.Tunes, t)
-	fmt.Println("Added:", title)
+	remember(data, "Add %s", t.Name)
+	data.DeliverTune(draw, t)
+	fmt.Fprintln(con.Out, i18n.T("Added: %s", t.Name))
 }
 
 func AddTune(data *core.Data, scanner *bufio.Scanner) {
 	ClearScreen()
 	PrintTunesdayHeader()
-	fmt.Println("Manually add a tune to list")
-	fmt.Println("Paste the link (any), and the title shown in the list will be the URL host/path.")
-	fmt.Print("Link: ")
+	fmt.Fprintln(con.Out, i18n.T("Manually add a tune to list"))
+	fmt.Fprintln(con.Out, i18n.T("Paste the link (any), and the title shown in the list will be the URL host/path."))
+	fmt.Fprint(con.Out, i18n.T("Link: "))
 	if !scanner.Scan() {
 		return
 	}
@@ -119,62 +259,142 @@ func AddTune(data *core.Data, scanner *bufio.Scanner) {
 		return
 	}
 	// keep only minimal info (no auto title)
-	t := core.Tune{Link: link, Provider: "manual", AddedAt: time.Now()}
+	t := core.Tune{Link: link, Provider: "manual", AddedAt: con.Now()}
+	if s := data.LookupSession(t.AddedAt); s != nil {
+		t.Theme = s.Theme
+	}
+	remember(data, "Add %s", link)
 	data.Tunes = append(data.Tunes, t)
-	fmt.Println("Added.")
+	fmt.Fprintln(con.Out, i18n.T("Added."))
+}
+
+// PlanRound sets the theme and the number of providers for today's session.
+func PlanRound(ctx context.Context, data *core.Data, scanner *bufio.Scanner) {
+	for {
+		theme, slots := i18n.T("none"), 1
+		if s := data.LookupSession(con.Now()); s != nil {
+			if s.Theme != "" {
+				theme = s.Theme
+			}
+			slots = s.ProviderSlots()
+			SetSessionTheme(s.Theme)
+		}
+		idx := ShowMenu(ctx, i18n.T("Plan todays round (theme: %s, providers: %d)", theme, slots), []string{
+			i18n.T("Draw a theme from the catalogue"),
+			i18n.T("Set theme by hand"),
+			i18n.T("Clear theme"),
+			i18n.T("Number of providers"),
+			i18n.T("Manage theme catalogue"),
+			i18n.T("Back"),
+		})
+		switch idx {
+		case -1:
+			quit()
+		case 0: // Draw theme
+			DrawTheme(data)
+			PressEnterToContinue()
+		case 1: // Set by hand
+			fmt.Fprint(con.Out, i18n.T("Theme: "))
+			if !scanner.Scan() {
+				continue
+			}
+			name := strings.TrimSpace(scanner.Text())
+			if name == "" {
+				continue
+			}
+			_ = rememberIf(data, func() error {
+				session := data.SessionOn(con.Now())
+				if t := data.ThemeByName(name); t != nil {
+					session.SetTheme(t)
+				} else {
+					session.Theme, session.ThemeID = name, ""
+				}
+				return nil
+			}, "Set theme to %s", name)
+		case 2: // Clear
+			if s := data.LookupSession(con.Now()); s != nil {
+				_ = rememberIf(data, func() error {
+					s.Theme, s.ThemeID = "", ""
+					return nil
+				}, "Clear theme")
+			}
+		case 3: // Providers
+			fmt.Fprint(con.Out, i18n.T("How many providers today? (current: %d): ", slots))
+			if !scanner.Scan() {
+				continue
+			}
+			raw := strings.TrimSpace(scanner.Text())
+			if raw == "" {
+				continue
+			}
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				fmt.Fprintln(con.Out, i18n.T("Please enter a number of at least 1."))
+				PressEnterToContinue()
+				continue
+			}
+			_ = rememberIf(data, func() error {
+				data.SessionOn(con.Now()).Slots = n
+				return nil
+			}, "Set providers to %d", n)
+		case 4: // Catalogue
+			ManageThemes(ctx, data, scanner)
+		case 5, -2:
+			return
+		}
+	}
 }
 
-func ListTunes(data *core.Data, scanner *bufio.Scanner) {
+// DrawTheme spins the roulette over the themes that are out of cooldown and
+// plays the winner in today's session.
+func DrawTheme(data *core.Data) {
 	ClearScreen()
 	PrintTunesdayHeader()
-	fmt.Println("Get complete list of tunes")
-	fmt.Println("Total tunes:", len(data.Tunes))
-	fmt.Println("")
-
-	// columns
-	w := termWidth()
-	nameW := 52
-	linkW := 26
-	dateW := 16
-	if w < 90 {
-		// shrink proportionally
-		nameW = 36
-		linkW = 20
-		dateW = 12
-	}
-
-	fmt.Println(PadRight("Title", nameW) + "  " + PadRight("Link", linkW) + "  Date")
-	fmt.Println(strings.Repeat("-", nameW+linkW+dateW+4))
-	for _, t := range data.Tunes {
-		title := t.Name
-		if title == "" {
-			title = linkDisplay(t.Link)
+	now := con.Now()
+	available := data.AvailableThemes(now)
+	if len(available) == 0 {
+		if len(data.Themes) == 0 {
+			fmt.Fprintln(con.Out, i18n.T("The theme catalogue is empty. Add some themes first."))
+		} else {
+			fmt.Fprintln(con.Out, i18n.T("All themes are cooling down. Add more themes or shorten their cooldowns."))
 		}
-		title = TruncateRunes(title, nameW)
-		link := TruncateRunes(linkDisplay(t.Link), linkW)
-		date := t.AddedAt.Format("2006-01-02")
-		if t.AddedAt.IsZero() {
-			date = ""
-		}
-		fmt.Println(PadRight(title, nameW) + "  " + PadRight(link, linkW) + "  " + date)
+		return
+	}
+
+	names := make([]string, len(available))
+	for i, t := range available {
+		names[i] = t.Name
 	}
+	winner := con.Rand.Intn(len(available))
+	spin(i18n.T("Drawing today's theme…"), names, []int{winner})
+
+	session := data.SessionOn(now)
+	_ = rememberIf(data, func() error {
+		session.SetTheme(available[winner])
+		return nil
+	}, "Draw a theme from the catalogue")
+	SetSessionTheme(session.Theme)
+
+	ClearScreen()
+	PrintTunesdayHeader()
+	drawBanner(i18n.T("Today's theme: %s", session.Theme))
 }
 
-func ManageParticipants(ctx context.Context, data *core.Data, scanner *bufio.Scanner) {
+// ManageThemes edits the theme catalogue.
+func ManageThemes(ctx context.Context, data *core.Data, scanner *bufio.Scanner) {
 	for {
-		idx := ShowMenu(ctx, "Manage Tunesday participants", []string{
-			"Add",
-			"Remove",
-			"List",
-			"Activate/Deactivate",
-			"Back",
+		idx := ShowMenu(ctx, i18n.T("Manage theme catalogue"), []string{
+			i18n.T("Add"),
+			i18n.T("Remove"),
+			i18n.T("List"),
+			i18n.T("Change cooldown"),
+			i18n.T("Back"),
 		})
 		switch idx {
 		case -1:
-			fmt.Println("Goodbye!")
-			os.Exit(0)
+			quit()
 		case 0: // Add
-			fmt.Print("Enter participant name: ")
+			fmt.Fprint(con.Out, i18n.T("Theme name: "))
 			if !scanner.Scan() {
 				continue
 			}
@@ -182,132 +402,419 @@ func ManageParticipants(ctx context.Context, data *core.Data, scanner *bufio.Sca
 			if name == "" {
 				continue
 			}
-			if data.Participants == nil {
-				data.Participants = map[string]int{}
+			weeks, ok := readCooldown(scanner, core.DefaultThemeCooldownWeeks)
+			if !ok {
+				continue
 			}
-			if _, exists := data.Participants[name]; exists {
-				fmt.Println("Participant already exists.")
+			err := rememberIf(data, func() error {
+				_, err := data.AddTheme(name, weeks)
+				return err
+			}, "Add theme %s", name)
+			if errors.Is(err, core.ErrThemeExists) {
+				fmt.Fprintln(con.Out, i18n.T("Theme already exists."))
 			} else {
-				data.Participants[name] = 0
-				fmt.Println("Participant added.")
+				fmt.Fprintln(con.Out, i18n.T("Theme added."))
 			}
 			PressEnterToContinue()
 		case 1: // Remove
-			if len(data.Participants) == 0 {
-				fmt.Println("No participants.")
+			t := pickTheme(ctx, data, i18n.T("Select theme to remove"))
+			if t == nil || !confirm(ctx, i18n.T("Remove the theme %s?", t.Name), i18n.T("Yes, remove it")) {
+				continue
+			}
+			if err := rememberIf(data, func() error { return data.RemoveTheme(t.ID) }, "Remove theme %s", t.Name); err != nil {
+				fmt.Fprintln(con.Out, err)
+			} else {
+				fmt.Fprintln(con.Out, i18n.T("Removed."))
+			}
+			PressEnterToContinue()
+		case 2: // List
+			ClearScreen()
+			PrintTunesdayHeader()
+			if len(data.Themes) == 0 {
+				fmt.Fprintln(con.Out, i18n.T("No themes."))
+			} else {
+				fmt.Fprintln(con.Out, i18n.T("Themes:"))
+				now := con.Now()
+				for _, t := range data.SortedThemes() {
+					status := i18n.T("never played")
+					if last := data.ThemeLastUsed(t); !last.IsZero() {
+						status = i18n.T("last played %s", i18n.Date(last))
+						if next := data.ThemeAvailableFrom(t, now); next.After(now) {
+							status = i18n.T("last played %s, cooling down until %s", i18n.Date(last), i18n.Date(next))
+						}
+					}
+					cooldown := i18n.N(t.CooldownWeeks, "cooldown: %d week", "cooldown: %d weeks", t.CooldownWeeks)
+					fmt.Fprintf(con.Out, "  %s  (%s, %s)\n", t.Name, cooldown, status)
+				}
+			}
+			PressEnterToContinue()
+		case 3: // Cooldown
+			t := pickTheme(ctx, data, i18n.T("Select theme"))
+			if t == nil {
+				continue
+			}
+			if weeks, ok := readCooldown(scanner, t.CooldownWeeks); ok {
+				_ = rememberIf(data, func() error {
+					t.CooldownWeeks = weeks
+					return nil
+				}, "Change cooldown of %s", t.Name)
+			}
+		case 4, -2:
+			return
+		}
+	}
+}
+
+func readCooldown(scanner *bufio.Scanner, def int) (int, bool) {
+	fmt.Fprint(con.Out, i18n.T("Cooldown in weeks (Enter for %d): ", def))
+	if !scanner.Scan() {
+		return 0, false
+	}
+	raw := strings.TrimSpace(scanner.Text())
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		fmt.Fprintln(con.Out, i18n.T("Please enter a number of weeks."))
+		PressEnterToContinue()
+		return 0, false
+	}
+	return n, true
+}
+
+// pickTheme shows the catalogue in a menu and returns the chosen theme, or nil.
+func pickTheme(ctx context.Context, data *core.Data, title string) *core.Theme {
+	ts := data.SortedThemes()
+	if len(ts) == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No themes."))
+		PressEnterToContinue()
+		return nil
+	}
+	names := make([]string, len(ts))
+	for i, t := range ts {
+		names[i] = t.Name
+	}
+	sel := ShowMenu(ctx, title, names)
+	switch sel {
+	case -1:
+		quit()
+	case -2:
+		return nil
+	}
+	return ts[sel]
+}
+
+// pickThemeFilter lets the user narrow a list to one theme. It returns "" for
+// all tunes and false when the user backed out.
+func pickThemeFilter(ctx context.Context, data *core.Data, title string) (string, bool) {
+	themes := data.TuneThemes()
+	if len(themes) == 0 {
+		return "", true
+	}
+	sel := ShowMenu(ctx, title, append([]string{i18n.T("All tunes")}, themes...))
+	switch sel {
+	case -1:
+		quit()
+	case -2:
+		return "", false
+	case 0:
+		return "", true
+	}
+	return themes[sel-1], true
+}
+
+// RateTunes lets a participant rate tunes. Everybody has one vote per tune;
+// voting again replaces the earlier vote.
+func RateTunes(ctx context.Context, data *core.Data) {
+	if len(data.Tunes) == 0 {
+		ClearScreen()
+		PrintTunesdayHeader()
+		fmt.Fprintln(con.Out, i18n.T("No tunes yet."))
+		PressEnterToContinue()
+		return
+	}
+	voter := pickParticipant(ctx, i18n.T("Who is voting?"), data.ActiveParticipants())
+	if voter == nil {
+		return
+	}
+	for {
+		// newest first
+		items := make([]string, len(data.Tunes))
+		for i := range data.Tunes {
+			t := data.Tunes[len(data.Tunes)-1-i]
+			mine := i18n.T("not rated")
+			if v, ok := t.Votes[voter.ID]; ok {
+				mine = i18n.T("you: %s", stars(v))
+			}
+			items[i] = fmt.Sprintf("%s  [%s]", TruncateRunes(t.Title(), 50), i18n.T("%s, score %s", mine, t.ScoreLabel()))
+		}
+		sel := ShowMenu(ctx, i18n.T("%s, pick a tune to rate (Esc when done)", voter.Name), items)
+		switch sel {
+		case -1:
+			quit()
+		case -2:
+			return
+		}
+		i := len(data.Tunes) - 1 - sel
+
+		ratings := []int{5, 4, 3, 2, 1, core.ThumbsUp, core.ThumbsDown, 0}
+		r := ShowMenu(ctx, i18n.T("Rate %s", data.Tunes[i].Title()), []string{
+			stars(5), stars(4), stars(3), stars(2), stars(1),
+			i18n.T("👍 Thumbs up"),
+			i18n.T("👎 Thumbs down"),
+			i18n.T("Withdraw my vote"),
+		})
+		switch r {
+		case -1:
+			quit()
+		case -2:
+			continue
+		}
+		_ = rememberIf(data, func() error { return data.Vote(i, voter.ID, ratings[r]) }, "Rate %s", data.Tunes[i].Title())
+	}
+}
+
+func ManageParticipants(ctx context.Context, data *core.Data, scanner *bufio.Scanner) {
+	for {
+		idx := ShowMenu(ctx, i18n.T("Manage Tunesday participants"), []string{
+			i18n.T("Add"),
+			i18n.T("Rename"),
+			i18n.T("Remove"),
+			i18n.T("List"),
+			i18n.T("Activate/Deactivate"),
+			i18n.T("Archive (left the team)"),
+			i18n.T("Restore from archive"),
+			i18n.T("Back"),
+		})
+		switch idx {
+		case -1:
+			quit()
+		case 0: // Add
+			fmt.Fprint(con.Out, i18n.T("Enter participant name: "))
+			if !scanner.Scan() {
+				continue
+			}
+			name := strings.TrimSpace(scanner.Text())
+			if name == "" {
+				continue
+			}
+			if p := data.ParticipantByName(name); p != nil && p.Archived {
+				fmt.Fprintln(con.Out, i18n.T("%s is archived. Restore them from the archive instead.", p.Name))
 				PressEnterToContinue()
 				continue
 			}
-			names := make([]string, 0, len(data.Participants))
-			for n := range data.Participants {
-				names = append(names, n)
+			err := rememberIf(data, func() error {
+				_, err := data.AddParticipant(name)
+				return err
+			}, "Add %s", name)
+			if errors.Is(err, core.ErrParticipantExists) {
+				fmt.Fprintln(con.Out, i18n.T("Participant already exists."))
+			} else {
+				fmt.Fprintln(con.Out, i18n.T("Participant added."))
 			}
-			sort.Strings(names)
-			sel := ShowMenu(ctx, "Select participant to remove", names)
-			switch sel {
-			case -1:
-				fmt.Println("Goodbye!")
-				os.Exit(0)
-			case -2:
+			PressEnterToContinue()
+		case 1: // Rename
+			p := pickParticipant(ctx, i18n.T("Select participant to rename"), data.CurrentParticipants())
+			if p == nil {
 				continue
 			}
-			delete(data.Participants, names[sel])
-			if data.Disabled != nil {
-				delete(data.Disabled, names[sel])
+			fmt.Fprint(con.Out, i18n.T("New name for %s: ", p.Name))
+			if !scanner.Scan() {
+				continue
 			}
-			// remove their tunes
-			newTunes := data.Tunes[:0]
-			for _, t := range data.Tunes {
-				if t.Provider != names[sel] {
-					newTunes = append(newTunes, t)
-				}
+			name := strings.TrimSpace(scanner.Text())
+			if name == "" {
+				continue
+			}
+			old := p.Name
+			err := rememberIf(data, func() error { return data.RenameParticipant(p.ID, name) }, "Rename %s", old)
+			if errors.Is(err, core.ErrParticipantExists) {
+				fmt.Fprintln(con.Out, i18n.T("Another participant already uses that name."))
+			} else {
+				fmt.Fprintln(con.Out, i18n.T("%s is now %s.", old, p.Name))
 			}
-			data.Tunes = newTunes
-			fmt.Println("Removed.")
 			PressEnterToContinue()
-		case 2: // List
+		case 2: // Remove
+			p := pickParticipant(ctx, i18n.T("Select participant to remove"), data.CurrentParticipants())
+			if p == nil {
+				continue
+			}
+			switch ShowMenu(ctx, removeQuestion(data, p), []string{
+				i18n.T("No, keep it"),
+				i18n.T("Archive %s instead (keeps their history)", p.Name),
+				i18n.T("Yes, remove %s", p.Name),
+			}) {
+			case -1:
+				quit()
+			case 1:
+				archiveParticipant(data, p)
+			case 2:
+				if err := rememberIf(data, func() error { return data.RemoveParticipant(p.ID) }, "Remove %s", p.Name); err != nil {
+					fmt.Fprintln(con.Out, err)
+				} else {
+					fmt.Fprintln(con.Out, i18n.T("Removed."))
+				}
+				PressEnterToContinue()
+			}
+		case 3: // List
 			if len(data.Participants) == 0 {
-				fmt.Println("No participants.")
+				fmt.Fprintln(con.Out, i18n.T("No participants."))
 			} else {
 				ClearScreen()
 				PrintTunesdayHeader()
-				fmt.Println("Participants:")
-				names := make([]string, 0, len(data.Participants))
-				for n := range data.Participants {
-					names = append(names, n)
+				fmt.Fprintln(con.Out, i18n.T("Participants:"))
+				counts := data.Counts()
+				for _, p := range data.CurrentParticipants() {
+					status := i18n.T("active")
+					if p.Disabled {
+						status = i18n.T("deactivated")
+					}
+					printParticipant(p, counts[p.ID], status)
 				}
-				sort.Strings(names)
-				for _, name := range names {
-					count := data.Participants[name]
-					status := "active"
-					if data.Disabled != nil && data.Disabled[name] {
-						status = "deactivated"
+				if archived := data.ArchivedParticipants(); len(archived) > 0 {
+					fmt.Fprintln(con.Out, "")
+					fmt.Fprintln(con.Out, i18n.T("Archived:"))
+					for _, p := range archived {
+						printParticipant(p, counts[p.ID], i18n.T("archived %s", i18n.Date(p.ArchivedAt)))
 					}
-					fmt.Printf("  %s  (tunes: %d, %s)\n", name, count, status)
 				}
 			}
 			PressEnterToContinue()
-		case 3: // Activate/Deactivate
-			if len(data.Participants) == 0 {
-				fmt.Println("No participants.")
-				PressEnterToContinue()
+		case 4: // Activate/Deactivate
+			p := pickParticipant(ctx, i18n.T("Select participant to toggle activation"), data.CurrentParticipants())
+			if p == nil {
 				continue
 			}
-			names := make([]string, 0, len(data.Participants))
-			for n := range data.Participants {
-				names = append(names, n)
+			if p.Disabled {
+				remember(data, "Activate %s", p.Name)
+			} else {
+				remember(data, "Deactivate %s", p.Name)
 			}
-			sort.Strings(names)
-			sel := ShowMenu(ctx, "Select participant to toggle activation", names)
-			switch sel {
-			case -1:
-				fmt.Println("Goodbye!")
-				os.Exit(0)
-			case -2:
+			p.Disabled = !p.Disabled
+			if p.Disabled {
+				fmt.Fprintln(con.Out, i18n.T("%s deactivated.", p.Name))
+			} else {
+				fmt.Fprintln(con.Out, i18n.T("%s activated.", p.Name))
+			}
+			PressEnterToContinue()
+		case 5: // Archive
+			if p := pickParticipant(ctx, i18n.T("Who left the team?"), data.CurrentParticipants()); p != nil {
+				archiveParticipant(data, p)
+			}
+		case 6: // Restore
+			archived := data.ArchivedParticipants()
+			if len(archived) == 0 {
+				fmt.Fprintln(con.Out, i18n.T("Nobody is archived."))
+				PressEnterToContinue()
 				continue
 			}
-			name := names[sel]
-			if data.Disabled == nil {
-				data.Disabled = make(map[string]bool)
+			p := pickParticipant(ctx, i18n.T("Who is back?"), archived)
+			if p == nil {
+				continue
 			}
-			data.Disabled[name] = !data.Disabled[name]
-			if data.Disabled[name] {
-				fmt.Printf("%s deactivated.\n", name)
+			if err := rememberIf(data, func() error { return data.RestoreParticipant(p.ID) }, "Restore %s", p.Name); err != nil {
+				fmt.Fprintln(con.Out, err)
 			} else {
-				fmt.Printf("%s activated.\n", name)
+				fmt.Fprintln(con.Out, i18n.T("Welcome back, %s!", p.Name))
 			}
 			PressEnterToContinue()
-		case 4, -2:
+		case 7, -2:
 			return
 		}
 	}
 }
 
-func PrintYouTubePlaylistLink(data *core.Data) {
+// archiveParticipant archives p, who then no longer shows up in draws and
+// menus but keeps their tunes.
+func archiveParticipant(data *core.Data, p *core.Participant) {
+	if err := rememberIf(data, func() error { return data.ArchiveParticipant(p.ID, con.Now()) }, "Archive %s", p.Name); err != nil {
+		fmt.Fprintln(con.Out, err)
+	} else {
+		fmt.Fprintln(con.Out, i18n.T("%s is archived. Their tunes stay in the list and the statistics.", p.Name))
+	}
+	PressEnterToContinue()
+}
+
+// printParticipant prints a line of the participant list.
+func printParticipant(p *core.Participant, c core.Counts, status string) {
+	fmt.Fprintf(con.Out, "  %s  (%s)\n", p.Name, i18n.T("tunes: %d, drawn: %d, skipped: %d, swapped: %d, volunteered: %d, %s",
+		c.Delivered, c.Drawn, c.Skipped, c.Swapped, c.Volunteered, status))
+	if c.Owes > 0 {
+		fmt.Fprintln(con.Out, "      "+i18n.N(c.Owes, "owes %d tune", "owes %d tunes", c.Owes))
+	}
+}
+
+// removeQuestion asks whether to remove p, warning about the tunes that go
+// with them.
+func removeQuestion(data *core.Data, p *core.Participant) string {
+	n := 0
+	for _, t := range data.Tunes {
+		if t.ParticipantID == p.ID {
+			n++
+		}
+	}
+	if n == 0 {
+		return i18n.T("Remove %s?", p.Name)
+	}
+	return i18n.N(n, "Remove %s and their %d tune?", "Remove %s and their %d tunes?", p.Name, n)
+}
+
+// pickParticipant shows the participants in a menu and returns the chosen one,
+// or nil when there is nobody to choose or the user backed out.
+func pickParticipant(ctx context.Context, title string, ps []*core.Participant) *core.Participant {
+	if len(ps) == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No participants."))
+		PressEnterToContinue()
+		return nil
+	}
+	names := make([]string, len(ps))
+	for i, p := range ps {
+		names[i] = p.Name
+	}
+	sel := ShowMenu(ctx, title, names)
+	switch sel {
+	case -1:
+		quit()
+	case -2:
+		return nil
+	}
+	return ps[sel]
+}
+
+func PrintYouTubePlaylistLink(ctx context.Context, data *core.Data) {
+	theme, ok := pickThemeFilter(ctx, data, i18n.T("Playlist of which tunes?"))
+	if !ok {
+		return
+	}
+	tunes := data.TunesWithTheme(theme)
 	ClearScreen()
 	PrintTunesdayHeader()
-	if len(data.Tunes) == 0 {
-		fmt.Println("No tunes yet.")
+	if len(tunes) == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No tunes yet."))
 		return
 	}
-	ids := make([]string, 0, len(data.Tunes))
-	for _, t := range data.Tunes {
+	ids := make([]string, 0, len(tunes))
+	for _, t := range tunes {
 		if t.ID != "" {
 			ids = append(ids, t.ID)
 		}
 	}
 	if len(ids) == 0 {
-		fmt.Println("No valid YouTube video IDs found to build a playlist (no tunes with titles).")
+		fmt.Fprintln(con.Out, i18n.T("No valid YouTube video IDs found to build a playlist (no tunes with titles)."))
 		return
 	}
-	link := "https://www.youtube.com/watch_videos?video_ids=" + strings.Join(ids, ",")
-	fmt.Println("Get youtube playlist link")
-	fmt.Println("")
-	fmt.Println(link)
-	fmt.Println("")
-	fmt.Println("Links for pasting: (https://www.terrific.tools/youtube/playlist-generator)")
-	fmt.Println("")
-	for _, t := range data.Tunes {
-		fmt.Println(t.Link)
+	link := playlist.WatchURL(ids)
+	fmt.Fprintln(con.Out, i18n.T("Get youtube playlist link"))
+	if theme != "" {
+		fmt.Fprintln(con.Out, i18n.T("Theme: %s", theme))
+	}
+	fmt.Fprintln(con.Out, "")
+	fmt.Fprintln(con.Out, link)
+	fmt.Fprintln(con.Out, "")
+	fmt.Fprintln(con.Out, i18n.T("Links for pasting: (https://www.terrific.tools/youtube/playlist-generator)"))
+	fmt.Fprintln(con.Out, "")
+	for _, t := range tunes {
+		fmt.Fprintln(con.Out, t.Link)
 	}
 }
@@ -0,0 +1,531 @@
+package termui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"atomicgo.dev/keyboard/keys"
+
+	"tunesday/internal/core"
+	"tunesday/internal/golden"
+	"tunesday/internal/i18n"
+	"tunesday/internal/playlist"
+)
+
+var testStart = time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC)
+
+// run plays screen on a console scripted by s and returns everything drawn.
+// A scripted Exit ends the screen instead of the test.
+func run(t *testing.T, s Script, screen func()) (out string) {
+	t.Helper()
+	if s.Start.IsZero() {
+		s.Start = testStart
+	}
+	var buf bytes.Buffer
+	prev, prevTheme := con, sessionTheme
+	Use(s.Console(&buf))
+	t.Cleanup(func() {
+		Use(prev)
+		SetSessionTheme(prevTheme)
+	})
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(ExitCode); !ok {
+				panic(r)
+			}
+		}
+		out = buf.String()
+	}()
+	screen()
+	return buf.String()
+}
+
+// frames splits output at every screen clear, dropping repeated frames such
+// as the still frames of an animation.
+func frames(out string) []string {
+	var fs []string
+	for _, f := range strings.Split(out, "\x1b[H\x1b[2J") {
+		if f != "" && (len(fs) == 0 || fs[len(fs)-1] != f) {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+func transcript(out string) string {
+	return strings.Join(frames(out), "\n──── clear screen ────\n")
+}
+
+// pageFrame returns the first frame waiting for Enter, i.e. an information
+// page rather than a menu.
+func pageFrame(t *testing.T, out string) string {
+	t.Helper()
+	for _, f := range frames(out) {
+		if strings.Contains(f, "Press Enter to continue") {
+			return f
+		}
+	}
+	t.Fatalf("no page in output:\n%s", out)
+	return ""
+}
+
+func lastFrame(out string) string {
+	fs := frames(out)
+	if len(fs) == 0 {
+		return ""
+	}
+	return fs[len(fs)-1]
+}
+
+func repeat(key string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = key
+	}
+	return out
+}
+
+func testData(t *testing.T) *core.Data {
+	t.Helper()
+	d := core.NewData()
+	ann, _ := d.AddParticipant("Ann")
+	bob, _ := d.AddParticipant("Bob")
+	cid, _ := d.AddParticipant("Cid")
+	for _, p := range []*core.Participant{ann, bob, cid} {
+		p.JoinedAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	cid.Disabled = true
+	day := func(m time.Month, dd int) time.Time { return time.Date(2026, m, dd, 19, 0, 0, 0, time.UTC) }
+	d.Tunes = []core.Tune{
+		{Name: "Bohemian Rhapsody", Link: "https://www.youtube.com/watch?v=fJ9rUzIMcZQ", ID: "fJ9rUzIMcZQ", Provider: "youtube",
+			Channel: "Queen", Seconds: 355, ParticipantID: ann.ID, AddedAt: day(9, 1), Votes: map[string]int{bob.ID: 5, cid.ID: 4}},
+		{Name: "Hallelujah", Link: "https://artist.bandcamp.com/track/hallelujah", Provider: "manual",
+			ParticipantID: bob.ID, AddedAt: day(9, 8), Tags: []string{"cover"}, Theme: "Covers"},
+		{Name: "Around the World", Link: "https://www.youtube.com/watch?v=K0HSD_i2DvA", ID: "K0HSD_i2DvA", Provider: "youtube",
+			ParticipantID: ann.ID, AddedAt: day(9, 15)},
+	}
+	for _, tu := range d.Tunes {
+		d.SessionOn(tu.AddedAt).AddDraw(tu.ParticipantID, tu.AddedAt).Outcome = core.OutcomeDelivered
+	}
+	return d
+}
+
+func TestShowMenuScrolls(t *testing.T) {
+	items := make([]string, 30)
+	for i := range items {
+		items[i] = "Item " + string(rune('A'+i%26)) + strings.Repeat("!", i/26)
+	}
+	var sel int
+	ks := Press("down", "down", "down", "pgdown", "down", "down", "down", "down", "enter")
+	out := run(t, Script{Keys: ks, Height: 24}, func() {
+		sel = ShowMenu(context.Background(), "Pick one", items)
+	})
+	if sel != 19 {
+		t.Fatalf("selected %d, want 19", sel)
+	}
+	golden.Check(t, "menu_scrolled", lastFrame(out))
+}
+
+func TestShowMenuEscAndCtrlC(t *testing.T) {
+	for key, want := range map[string]int{"esc": -2, "ctrl+c": -1} {
+		var sel int
+		run(t, Script{Keys: Press(key)}, func() { sel = ShowMenu(context.Background(), "", []string{"a"}) })
+		if sel != want {
+			t.Errorf("%s: got %d, want %d", key, sel, want)
+		}
+	}
+}
+
+func TestShowMenuShortcuts(t *testing.T) {
+	items := []string{"Ann", "Bob", "Cid", "Dee", "Bea"}
+	for _, tc := range []struct {
+		name string
+		keys []keys.Key
+		want int
+	}{
+		{"up wraps to the end", Press("up", "enter"), 4},
+		{"down wraps to the start", Press("end", "down", "enter"), 0},
+		{"j and k", append(Type("jjjk"), Press("enter")...), 2},
+		{"k wraps", append(Type("k"), Press("enter")...), 4},
+		{"G and g", append(Type("Gg"), Press("enter")...), 0},
+		{"G", append(Type("G"), Press("enter")...), 4},
+		{"number", Type("4"), 3},
+		{"number out of range", append(Type("7"), Press("enter")...), 0},
+		{"filter keeps the original index", append(Type("be"), Press("enter")...), 4},
+		{"filter with slash", append(Type("/e"), Press("down", "enter")...), 4},
+		{"j types while filtering", append(Type("/j"), Press("enter", "esc", "esc")...), -2},
+		{"backspace keeps the selection", append(Type("bea"), Press("backspace", "backspace", "enter")...), 4},
+		{"esc clears the filter first", append(Type("dee"), Press("esc", "enter")...), 3},
+		{"click", Click(5, 18), 1},
+		{"click outside the items", append(Click(5, 10), Press("enter")...), 0},
+		{"wheel", append(Wheel(3), append(Wheel(-1), Press("enter")...)...), 2},
+	} {
+		var sel int
+		run(t, Script{Keys: tc.keys}, func() { sel = ShowMenu(context.Background(), "Pick one", items) })
+		if sel != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, sel, tc.want)
+		}
+	}
+}
+
+func TestShowMenuFilterFrame(t *testing.T) {
+	items := []string{"Ann", "Bob", "Cid", "Dee", "Bea"}
+	out := run(t, Script{Keys: Type("b")}, func() { ShowMenu(context.Background(), "Pick one", items) })
+	golden.Check(t, "menu_filtered", lastFrame(out))
+}
+
+type fakeYouTube struct{}
+
+func (fakeYouTube) NormalizeYouTubeID(raw string) (string, bool) {
+	if i := strings.Index(raw, "youtu.be/"); i >= 0 {
+		return raw[i+len("youtu.be/"):], true
+	}
+	return "", false
+}
+
+func (fakeYouTube) FetchTitle(ctx context.Context, id string) (string, error) {
+	return "Title of " + id, nil
+}
+
+func (fakeYouTube) FetchInfo(ctx context.Context, id string) (playlist.VideoInfo, error) {
+	return playlist.VideoInfo{Title: "Title of " + id, Channel: "Channel", Duration: 3 * time.Minute}, nil
+}
+
+func TestSelectProviderAndAddTune(t *testing.T) {
+	d := testData(t)
+	bob := d.ParticipantByName("Bob")
+	bob.Disabled = true // leaves Ann as the only candidate
+
+	var draws []*core.Draw
+	out := run(t, Script{Keys: Press("enter"), Lines: []string{"https://youtu.be/abc123"}}, func() {
+		draws = SelectProvider(context.Background(), d)
+		for _, dr := range draws {
+			AddTuneWithProvider(context.Background(), d, Input(), dr, fakeYouTube{})
+		}
+	})
+	if len(draws) != 1 || draws[0].Outcome != core.OutcomeDelivered {
+		t.Fatalf("draws = %+v\n%s", draws, out)
+	}
+	if got := d.Tunes[len(d.Tunes)-1]; got.Name != "Title of abc123" || got.Seconds != 180 {
+		t.Fatalf("added tune = %+v", got)
+	}
+	golden.Check(t, "select_provider", transcript(out))
+}
+
+func TestSelectProviderPassAndReroll(t *testing.T) {
+	d := testData(t)
+
+	var draws []*core.Draw
+	run(t, Script{Keys: Press("down", "down", "down", "enter", "enter")}, func() {
+		draws = SelectProvider(context.Background(), d)
+	})
+	s := d.LookupSession(testStart)
+	if len(s.Draws) != 2 || s.Draws[0].Outcome != core.OutcomePassed {
+		t.Fatalf("session draws = %+v", s.Draws)
+	}
+	if len(draws) != 1 || draws[0] != s.Draws[1] || draws[0].ParticipantID == s.Draws[0].ParticipantID {
+		t.Fatalf("draws = %+v", draws)
+	}
+}
+
+func TestSelectProviderSwapSkipsLaterWinners(t *testing.T) {
+	d := testData(t)
+	d.AddParticipant("Dan")
+	d.SessionOn(testStart).Slots = 2
+
+	// the first winner swaps with the only volunteer left, who can't be the
+	// winner of the second slot
+	var draws []*core.Draw
+	out := run(t, Script{Keys: Press("down", "down", "enter", "enter", "enter")}, func() {
+		draws = SelectProvider(context.Background(), d)
+	})
+	if len(draws) != 2 || draws[0].ParticipantID == draws[1].ParticipantID {
+		t.Fatalf("draws = %+v\n%s", draws, out)
+	}
+	second := d.ParticipantName(draws[1].ParticipantID)
+	for _, f := range frames(out) {
+		if _, volunteers, ok := strings.Cut(f, "Who volunteers instead of"); ok && strings.Contains(volunteers, second) {
+			t.Errorf("%s, drawn for the second slot, is offered as a volunteer:\n%s", second, f)
+		}
+	}
+}
+
+func TestSelectProviderEscLeavesDrawPending(t *testing.T) {
+	d := testData(t)
+	before := d.Counts()
+
+	var draws []*core.Draw
+	run(t, Script{Keys: Press("esc")}, func() {
+		draws = SelectProvider(context.Background(), d)
+	})
+	s := d.LookupSession(testStart)
+	if len(draws) != 0 || len(s.Draws) != 1 || s.Draws[0].Outcome != core.OutcomePending {
+		t.Fatalf("draws = %+v, session draws = %+v", draws, s.Draws)
+	}
+	winner := s.Draws[0].ParticipantID
+
+	// coming back takes up the same draw instead of drawing again
+	out := run(t, Script{Keys: Press("enter")}, func() {
+		draws = SelectProvider(context.Background(), d)
+	})
+	if strings.Contains(out, "Selecting today's provider") {
+		t.Error("drew again instead of taking up the pending draw")
+	}
+	if len(draws) != 1 || draws[0] != s.Draws[0] || len(s.Draws) != 1 {
+		t.Fatalf("draws = %+v, session draws = %+v", draws, s.Draws)
+	}
+	if n := d.Counts()[winner].Drawn - before[winner].Drawn; n != 1 {
+		t.Errorf("drawn %d times today, want 1", n)
+	}
+}
+
+func TestListTunesSearchAndDetails(t *testing.T) {
+	d := testData(t)
+	ks := append(Type("hal"), Press("enter")...)
+	ks = append(ks, Press("enter")...) // Edit tags
+	ks = append(ks, Press("esc", "esc", "esc")...)
+
+	out := run(t, Script{Keys: ks, Lines: []string{"cover, live"}, Width: 90, Height: 30}, func() {
+		ListTunes(context.Background(), d, Input())
+	})
+	if got := d.Tunes[1].Tags; len(got) != 2 || got[1] != "live" {
+		t.Fatalf("tags = %v", got)
+	}
+	fs := frames(out)
+	golden.Check(t, "tune_list_search", fs[3])
+	golden.Check(t, "tune_details", fs[4])
+}
+
+func TestListTunesInGerman(t *testing.T) {
+	if err := i18n.Use("de"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { i18n.Use("en") })
+	d := testData(t)
+	out := run(t, Script{Keys: Press("down", "enter", "esc", "esc"), Width: 90, Height: 30}, func() {
+		ListTunes(context.Background(), d, Input())
+	})
+	fs := frames(out)
+	golden.Check(t, "tune_list_de", fs[1])
+	golden.Check(t, "tune_details_de", fs[2])
+}
+
+func TestListTunesFilterMenu(t *testing.T) {
+	d := testData(t)
+	// Tab, Rating, "rated 4+", back to list
+	ks := Press("tab", "down", "down", "down", "down", "enter", "down", "enter", "end", "enter", "esc")
+	out := run(t, Script{Keys: ks, Width: 90, Height: 30}, func() {
+		ListTunes(context.Background(), d, Input())
+	})
+	golden.Check(t, "tune_list_filtered", lastFrame(out))
+}
+
+func TestManageParticipantsList(t *testing.T) {
+	d := testData(t)
+	out := run(t, Script{Keys: Press("down", "down", "down", "enter", "esc"), Lines: []string{""}}, func() {
+		ManageParticipants(context.Background(), d, Input())
+	})
+	golden.Check(t, "participants_list", pageFrame(t, out))
+}
+
+func TestRemoveParticipantConfirmsAndUndoes(t *testing.T) {
+	d := testData(t)
+	h := &core.History{}
+	SetHistory(h)
+	t.Cleanup(func() { SetHistory(nil) })
+
+	// Remove Ann but keep her at the question, then remove her for real.
+	ks := Press("down", "down", "enter", "enter", "enter")
+	ks = append(ks, Press("down", "down", "enter", "enter", "end", "enter", "esc")...)
+	out := run(t, Script{Keys: ks, Lines: []string{""}}, func() {
+		ManageParticipants(context.Background(), d, Input())
+	})
+	if !strings.Contains(out, "Remove Ann and their 2 tunes?") {
+		t.Errorf("no confirmation in:\n%s", out)
+	}
+	if d.ParticipantByName("Ann") != nil || len(d.Tunes) != 1 {
+		t.Fatalf("Ann was not removed: %d tunes", len(d.Tunes))
+	}
+
+	out = run(t, Script{}, func() { Undo(d) })
+	if d.ParticipantByName("Ann") == nil || len(d.Tunes) != 3 {
+		t.Fatalf("undo did not bring Ann back: %d tunes", len(d.Tunes))
+	}
+	if want := "Undone: Remove Ann (19:00)"; !strings.Contains(out, want) {
+		t.Errorf("output %q lacks %q", out, want)
+	}
+	run(t, Script{}, func() { Redo(d) })
+	if d.ParticipantByName("Ann") != nil {
+		t.Fatal("redo did not remove Ann again")
+	}
+}
+
+func TestFailedAddLeavesHistoryAlone(t *testing.T) {
+	d := testData(t)
+	h := &core.History{}
+	SetHistory(h)
+	t.Cleanup(func() { SetHistory(nil) })
+	_ = h.Record("Add Dee", d, testStart)
+	if _, err := h.Undo(d); err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(name string) {
+		run(t, Script{Keys: Press("enter", "esc"), Lines: []string{name, ""}}, func() {
+			ManageParticipants(context.Background(), d, Input())
+		})
+	}
+	add("Ann") // there already
+	if len(h.Done) != 0 || len(h.Undone) != 1 {
+		t.Errorf("after adding Ann again: %d to undo, %d to redo, want 0 and 1", len(h.Done), len(h.Undone))
+	}
+	add("Dee")
+	if len(h.Done) != 1 || h.Done[0].Action != "Add Dee" || d.ParticipantByName("Dee") == nil {
+		t.Errorf("after adding Dee: done = %+v", h.Done)
+	}
+}
+
+func TestUnchangedDataLeavesHistoryAlone(t *testing.T) {
+	d := testData(t)
+	h := &core.History{}
+	SetHistory(h)
+	t.Cleanup(func() { SetHistory(nil) })
+
+	// Ann withdraws a vote she never gave, then gives one
+	run(t, Script{Keys: Press("enter", "enter", "end", "enter", "esc")}, func() {
+		RateTunes(context.Background(), d)
+	})
+	if len(h.Done) != 0 {
+		t.Errorf("withdrawing no vote made a step: %+v", h.Done)
+	}
+	run(t, Script{Keys: Press("enter", "enter", "down", "enter", "esc")}, func() {
+		RateTunes(context.Background(), d)
+	})
+	if len(h.Done) != 1 {
+		t.Errorf("rating made %d steps, want 1", len(h.Done))
+	}
+}
+
+func TestArchiveAndRestoreParticipant(t *testing.T) {
+	d := testData(t)
+	// Archive Ann, list everyone, then restore her.
+	ks := Press("end", "up", "up", "enter", "enter")
+	ks = append(ks, Press("home", "down", "down", "down", "enter")...)
+	out := run(t, Script{Keys: ks, Lines: []string{"", ""}}, func() {
+		ManageParticipants(context.Background(), d, Input())
+	})
+	ann := d.ParticipantByName("Ann")
+	if !ann.Archived || len(d.Tunes) != 3 {
+		t.Fatalf("Ann archived = %v with %d tunes", ann.Archived, len(d.Tunes))
+	}
+	for _, p := range d.ActiveParticipants() {
+		if p == ann {
+			t.Fatal("archived participant can still be drawn")
+		}
+	}
+	fs := frames(out)
+	golden.Check(t, "participants_archived", fs[len(fs)-2])
+
+	run(t, Script{Keys: Press("end", "up", "enter", "enter")}, func() {
+		ManageParticipants(context.Background(), d, Input())
+	})
+	if ann.Archived {
+		t.Fatal("Ann was not restored")
+	}
+}
+
+func TestStatisticsPages(t *testing.T) {
+	d := testData(t)
+	for i, name := range []string{"leaderboard", "over_time", "fair_share", "days_since", "platforms", "wrapped"} {
+		ks := append(repeat("down", i), "enter", "esc")
+		out := run(t, Script{Keys: Press(ks...), Lines: []string{""}, Width: 80, Height: 40}, func() {
+			ShowStatistics(context.Background(), d)
+		})
+		golden.Check(t, "stats_"+name, pageFrame(t, out))
+	}
+}
+
+func TestDrawBigWinnerFollowsWidth(t *testing.T) {
+	for _, w := range []int{40, 120} {
+		out := run(t, Script{Width: w}, func() { DrawBigWinner("Ann", "Covers") })
+		for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			plain := strings.NewReplacer("\x1b[1m", "", "\x1b[7m", "", "\x1b[0m", "").Replace(line)
+			if n := len([]rune(plain)); n > w {
+				t.Errorf("width %d: line is %d wide: %q", w, n, plain)
+			}
+		}
+	}
+}
+
+func TestAddTune(t *testing.T) {
+	d := testData(t)
+	out := run(t, Script{Lines: []string{"https://soundcloud.com/artist/track"}}, func() {
+		AddTune(d, Input())
+	})
+	if got := d.Tunes[len(d.Tunes)-1]; got.Link != "https://soundcloud.com/artist/track" || got.Provider != "manual" {
+		t.Fatalf("added tune = %+v", got)
+	}
+	golden.Check(t, "add_tune", lastFrame(out))
+}
+
+func TestPlanRound(t *testing.T) {
+	d := testData(t)
+	// Number of providers, then back
+	out := run(t, Script{Keys: Press("down", "down", "down", "enter", "esc"), Lines: []string{"2"}}, func() {
+		PlanRound(context.Background(), d, Input())
+	})
+	if s := d.LookupSession(testStart); s == nil || s.Slots != 2 {
+		t.Fatalf("session = %+v", s)
+	}
+	golden.Check(t, "plan_round", lastFrame(out))
+}
+
+func TestDrawTheme(t *testing.T) {
+	d := testData(t)
+	d.AddTheme("Covers", 4)
+	d.AddTheme("Eighties", 0)
+	d.AddTheme("Summer", 0)
+	out := run(t, Script{}, func() { DrawTheme(d) })
+	if s := d.LookupSession(testStart); s == nil || s.Theme == "" || s.Theme == "Covers" {
+		t.Fatalf("session = %+v", s)
+	}
+	golden.Check(t, "draw_theme", lastFrame(out))
+}
+
+func TestManageThemesList(t *testing.T) {
+	d := testData(t)
+	d.AddTheme("Covers", 4)
+	d.AddTheme("Summer", 0)
+	out := run(t, Script{Keys: Press("down", "down", "enter", "esc"), Lines: []string{""}}, func() {
+		ManageThemes(context.Background(), d, Input())
+	})
+	golden.Check(t, "themes_list", pageFrame(t, out))
+}
+
+func TestRateTunes(t *testing.T) {
+	d := testData(t)
+	// Ann rates the newest tune with four stars
+	out := run(t, Script{Keys: Press("enter", "enter", "down", "enter", "esc"), Width: 90}, func() {
+		RateTunes(context.Background(), d)
+	})
+	if v := d.Tunes[2].Votes[d.ParticipantByName("Ann").ID]; v != 4 {
+		t.Fatalf("Ann's vote = %d, want 4", v)
+	}
+	golden.Check(t, "rate_tunes", lastFrame(out))
+}
+
+func TestPrintYouTubePlaylistLink(t *testing.T) {
+	d := testData(t)
+	out := run(t, Script{Keys: Press("enter")}, func() {
+		PrintYouTubePlaylistLink(context.Background(), d)
+	})
+	golden.Check(t, "playlist_link", lastFrame(out))
+}
+
+func TestPrintNotTunesdayHeader(t *testing.T) {
+	out := run(t, Script{}, PrintNotTunesdayHeader)
+	golden.Check(t, "not_tunesday_header", out)
+}
@@ -0,0 +1,103 @@
+package termui
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"atomicgo.dev/keyboard/keys"
+)
+
+// ExitCode is what a scripted console's Exit panics with.
+type ExitCode int
+
+// Script describes a scripted console for tests: the key presses for menus
+// and lists, the lines typed at prompts, the terminal size and colours and
+// the time the clock starts at. The clock only moves when a screen sleeps.
+// The zero Colors draws without escape codes.
+type Script struct {
+	Keys          []keys.Key
+	Lines         []string
+	Width, Height int
+	Colors        Profile
+	Start         time.Time
+}
+
+// Console returns a console that replays the script and draws into out.
+// Once the keys run out it presses Ctrl-C.
+func (s Script) Console(out io.Writer) *Console {
+	next := 0
+	now := s.Start
+	w, h := s.Width, s.Height
+	if w == 0 {
+		w = 80
+	}
+	if h == 0 {
+		h = 24
+	}
+	return &Console{
+		Out: out,
+		In:  strings.NewReader(strings.Join(s.Lines, "\n") + "\n"),
+		Keys: func(onKey func(keys.Key) (bool, error)) error {
+			for {
+				key := keys.Key{Code: keys.CtrlC}
+				if next < len(s.Keys) {
+					key = s.Keys[next]
+					next++
+				}
+				if stop, err := onKey(key); stop || err != nil {
+					return err
+				}
+			}
+		},
+		Size:   func() (int, int) { return w, h },
+		Colors: s.Colors,
+		Now:    func() time.Time { return now },
+		Sleep:  func(d time.Duration) { now = now.Add(d) },
+		Rand:   rand.New(rand.NewSource(1)),
+		Exit:   func(code int) { panic(ExitCode(code)) },
+	}
+}
+
+var keyNames = map[string]keys.KeyCode{
+	"up":        keys.Up,
+	"down":      keys.Down,
+	"pgup":      keys.PgUp,
+	"pgdown":    keys.PgDown,
+	"home":      keys.Home,
+	"end":       keys.End,
+	"enter":     keys.Enter,
+	"esc":       keys.Esc,
+	"tab":       keys.Tab,
+	"space":     keys.Space,
+	"backspace": keys.Backspace,
+	"ctrl+c":    keys.CtrlC,
+}
+
+// Press returns the named keys, e.g. Press("down", "down", "enter").
+// It panics on unknown names.
+func Press(names ...string) []keys.Key {
+	out := make([]keys.Key, len(names))
+	for i, name := range names {
+		code, ok := keyNames[name]
+		if !ok {
+			panic("termui: unknown key " + name)
+		}
+		out[i] = keys.Key{Code: code}
+	}
+	return out
+}
+
+// Type returns the key presses for typing s, one rune at a time.
+func Type(s string) []keys.Key {
+	var out []keys.Key
+	for _, r := range s {
+		code := keys.RuneKey
+		if r == ' ' {
+			code = keys.Space
+		}
+		out = append(out, keys.Key{Code: code, Runes: []rune{r}})
+	}
+	return out
+}
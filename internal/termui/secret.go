@@ -0,0 +1,42 @@
+package termui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadSecret asks for a line without showing what is typed, such as a
+// passphrase. Without a terminal the line is read as it comes.
+func ReadSecret(prompt string) (string, error) {
+	fmt.Print(prompt)
+	if restore, ok := hideInput(); ok {
+		defer fmt.Println()
+		defer restore()
+	}
+	return readLine(os.Stdin)
+}
+
+// readLine reads up to the end of the line a byte at a time, so that
+// nothing after it is taken from r.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package termui
+
+import "os"
+
+// querySize is not supported here; callers fall back to $COLUMNS and $LINES.
+func querySize() (width, height int, ok bool) {
+	return 0, 0, false
+}
+
+// notifyResize does nothing; there is no resize signal on this platform.
+func notifyResize(c chan<- os.Signal) {}
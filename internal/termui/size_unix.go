@@ -0,0 +1,26 @@
+//go:build unix
+
+package termui
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// querySize asks the terminal on stdout, or failing that stdin, for its size.
+func querySize() (width, height int, ok bool) {
+	for _, f := range []*os.File{os.Stdout, os.Stdin} {
+		ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+		if err == nil && ws.Col > 0 && ws.Row > 0 {
+			return int(ws.Col), int(ws.Row), true
+		}
+	}
+	return 0, 0, false
+}
+
+// notifyResize relays SIGWINCH to c.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, unix.SIGWINCH)
+}
@@ -0,0 +1,181 @@
+package termui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tunesday/internal/config"
+	"tunesday/internal/i18n"
+)
+
+// Skin is the look of the text UI. It is called a skin rather than a theme
+// so it is not mixed up with the themes of Tunesday rounds.
+type Skin struct {
+	Name     string
+	Logo     Style  // the Tunesday header
+	Title    Style  // headings such as a tune's title
+	Accent   Style  // highlighted names, bars and sparklines
+	Selected Style  // the line under the cursor
+	Banner   Style  // winner and theme banners
+	Muted    Style  // hints and footers
+	Cursor   string // in front of the line under the cursor
+	Header   string // header style, see headerStyles
+}
+
+// Skins are the built-in skins.
+var Skins = map[string]Skin{
+	"classic": {
+		Name:     "classic",
+		Title:    mustStyle("bold"),
+		Accent:   mustStyle("bold cyan"),
+		Selected: mustStyle("bold"),
+		Banner:   mustStyle("bold reverse"),
+		Cursor:   "▶ ",
+		Header:   "classic",
+	},
+	"ocean": {
+		Name:     "ocean",
+		Logo:     mustStyle("#4fc3f7"),
+		Title:    mustStyle("bold #81d4fa"),
+		Accent:   mustStyle("#26c6da"),
+		Selected: mustStyle("bold #e0f7fa on #01579b"),
+		Banner:   mustStyle("bold #ffffff on #0277bd"),
+		Muted:    mustStyle("#78909c"),
+		Cursor:   "➜ ",
+		Header:   "classic",
+	},
+	"sunset": {
+		Name:     "sunset",
+		Logo:     mustStyle("#ff8a65"),
+		Title:    mustStyle("bold #ffcc80"),
+		Accent:   mustStyle("#f06292"),
+		Selected: mustStyle("bold #fff3e0 on #bf360c"),
+		Banner:   mustStyle("bold #fff3e0 on #d84315"),
+		Muted:    mustStyle("#a1887f"),
+		Cursor:   "♪ ",
+		Header:   "classic",
+	},
+	"mono": {
+		Name:     "mono",
+		Title:    mustStyle("bold"),
+		Accent:   mustStyle("bold"),
+		Selected: mustStyle("reverse"),
+		Banner:   mustStyle("bold reverse"),
+		Cursor:   "> ",
+		Header:   "compact",
+	},
+}
+
+// SkinNames returns the names of the built-in skins, sorted.
+func SkinNames() []string {
+	names := make([]string, 0, len(Skins))
+	for name := range Skins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var skin = Skins["classic"]
+
+// CurrentSkin returns the skin in use.
+func CurrentSkin() Skin { return skin }
+
+// UseSkin switches to a skin by name, looking in the user's skins first and
+// then in the built-in ones. An empty name keeps the classic skin.
+func UseSkin(name string, custom map[string]config.Skin) error {
+	if name == "" {
+		name = "classic"
+	}
+	if c, ok := custom[name]; ok {
+		s, err := SkinFromConfig(name, c)
+		if err != nil {
+			return err
+		}
+		skin = s
+		return nil
+	}
+	s, ok := Skins[name]
+	if !ok {
+		return fmt.Errorf("unknown skin %q (built-in skins: %s)", name, strings.Join(SkinNames(), ", "))
+	}
+	skin = s
+	return nil
+}
+
+// SkinFromConfig builds a user-defined skin on top of its base skin.
+func SkinFromConfig(name string, c config.Skin) (Skin, error) {
+	base := c.Base
+	if base == "" {
+		base = "classic"
+	}
+	s, ok := Skins[base]
+	if !ok {
+		return Skin{}, fmt.Errorf("skin %q: unknown base skin %q", name, base)
+	}
+	s.Name = name
+	for _, f := range []struct {
+		spec string
+		dst  *Style
+	}{
+		{c.Logo, &s.Logo},
+		{c.Title, &s.Title},
+		{c.Accent, &s.Accent},
+		{c.Selected, &s.Selected},
+		{c.Banner, &s.Banner},
+		{c.Muted, &s.Muted},
+	} {
+		if f.spec == "" {
+			continue
+		}
+		st, err := ParseStyle(f.spec)
+		if err != nil {
+			return Skin{}, fmt.Errorf("skin %q: %w", name, err)
+		}
+		*f.dst = st
+	}
+	if c.Cursor != "" {
+		s.Cursor = c.Cursor
+	}
+	if c.Header != "" {
+		if _, ok := headerStyles[c.Header]; !ok {
+			return Skin{}, fmt.Errorf("skin %q: unknown header %q", name, c.Header)
+		}
+		s.Header = c.Header
+	}
+	return s, nil
+}
+
+// PreviewSkin draws a sample of the screens in skin s.
+func PreviewSkin(s Skin) {
+	prev := skin
+	skin = s
+	defer func() { skin = prev }()
+
+	fmt.Fprintln(con.Out, skin.Title.Render(i18n.T("Skin: %s", s.Name)))
+	printHeader(tunesdayHeader)
+	fmt.Fprintln(con.Out, cursorLine(i18n.T("Select todays tune provider"), true))
+	fmt.Fprintln(con.Out, cursorLine(i18n.T("Get complete list of tunes"), false))
+	fmt.Fprintf(con.Out, "  Ann  %s 3\n", skin.Accent.Render("███████████████"))
+	fmt.Fprintln(con.Out, "  "+skin.Banner.Render("  "+i18n.T("%s is today's tune provider!!", "Ann")+"  "))
+	fmt.Fprintln(con.Out, skin.Muted.Render("  "+i18n.T("↓ %d more", 2)))
+}
+
+// inputCursor marks where typed text goes: a reverse-video block, or an
+// underscore without escape codes.
+func inputCursor() string {
+	if con.Colors == Plain {
+		return "_"
+	}
+	return Style{Reverse: true}.Render(" ")
+}
+
+// cursorLine returns line prefixed with the cursor when selected, or with
+// as many spaces otherwise.
+func cursorLine(line string, selected bool) string {
+	if selected {
+		return skin.Cursor + skin.Selected.Render(line)
+	}
+	return strings.Repeat(" ", len([]rune(skin.Cursor))) + line
+}
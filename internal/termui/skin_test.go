@@ -0,0 +1,126 @@
+package termui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"tunesday/internal/config"
+	"tunesday/internal/golden"
+	"tunesday/internal/stats"
+)
+
+func TestDetectProfile(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want Profile
+	}{
+		{map[string]string{"TERM": "xterm"}, ANSI16},
+		{map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{map[string]string{"TERM": "xterm-direct"}, TrueColor},
+		{map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, Mono},
+		{map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, Plain},
+		{map[string]string{"TERM": "dumb", "TUNESDAY_COLORS": "256"}, ANSI256},
+	}
+	for _, c := range cases {
+		if got := DetectProfile(func(k string) string { return c.env[k] }); got != c.want {
+			t.Errorf("%v: got %d, want %d", c.env, got, c.want)
+		}
+	}
+}
+
+func TestStyleRender(t *testing.T) {
+	s, err := ParseStyle("bold #ff8800 on navy")
+	if err == nil {
+		t.Fatalf("expected an error for an unknown colour, got %+v", s)
+	}
+	s, err = ParseStyle("bold #ff8800 on blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[Profile]string{
+		Plain:     "x",
+		Mono:      "\x1b[1mx\x1b[0m",
+		ANSI16:    "\x1b[1;33;44mx\x1b[0m",
+		ANSI256:   "\x1b[1;38;5;208;44mx\x1b[0m",
+		TrueColor: "\x1b[1;38;2;255;136;0;44mx\x1b[0m",
+	}
+	for p, want := range cases {
+		if got := s.render(p, "x"); got != want {
+			t.Errorf("profile %d: got %q, want %q", p, got, want)
+		}
+	}
+	if got := mustStyle("cyan").render(Mono, "x"); got != "\x1b[1mx\x1b[0m" {
+		t.Errorf("coloured style without colours should fall back to bold, got %q", got)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	for _, spec := range []string{"cyan", "bright-red", "grey", "208", "#f80", "#FF8800"} {
+		if _, err := ParseColor(spec); err != nil {
+			t.Errorf("%s: %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "256", "#12345", "blurple"} {
+		if _, err := ParseColor(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestSkinFromConfig(t *testing.T) {
+	s, err := SkinFromConfig("mine", config.Skin{Base: "ocean", Accent: "bright-green", Cursor: "» ", Header: "compact"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "mine" || s.Cursor != "» " || s.Header != "compact" || s.Banner != Skins["ocean"].Banner {
+		t.Fatalf("skin = %+v", s)
+	}
+	if s.Accent.render(ANSI16, "x") != "\x1b[92mx\x1b[0m" {
+		t.Fatalf("accent = %q", s.Accent.render(ANSI16, "x"))
+	}
+	for _, c := range []config.Skin{{Base: "nope"}, {Title: "bold nope"}, {Header: "nope"}} {
+		if _, err := SkinFromConfig("bad", c); err == nil {
+			t.Errorf("%+v: expected an error", c)
+		}
+	}
+	if err := UseSkin("nope", nil); err == nil || !strings.Contains(err.Error(), "classic") {
+		t.Errorf("unknown skin error should list the built-in skins, got %v", err)
+	}
+}
+
+func TestMenuInSkins(t *testing.T) {
+	t.Cleanup(func() { skin = Skins["classic"] })
+	for _, name := range []string{"ocean", "mono"} {
+		if err := UseSkin(name, nil); err != nil {
+			t.Fatal(err)
+		}
+		out := run(t, Script{Keys: Press("down", "enter"), Colors: TrueColor}, func() {
+			ShowMenu(context.Background(), "Tunesday Menu", []string{"Select todays tune provider", "Exit"})
+		})
+		golden.Check(t, "menu_"+name, lastFrame(out))
+	}
+}
+
+func TestWrappedInSkins(t *testing.T) {
+	t.Cleanup(func() { skin = Skins["classic"] })
+	if err := UseSkin("ocean", nil); err != nil {
+		t.Fatal(err)
+	}
+	d := testData(t)
+	wrapped := func(colors Profile) string {
+		return run(t, Script{Colors: colors}, func() {
+			stats.NewWrapped(d, 2026).WriteANSI(con.Out, 80, ReportStyles())
+		})
+	}
+	if out := wrapped(TrueColor); !strings.Contains(out, skin.Accent.codes(TrueColor)+"█") {
+		t.Errorf("the bars aren't in the skin's accent colour:\n%q", out)
+	}
+	if out := wrapped(Mono); strings.Contains(out, "\x1b[38;") {
+		t.Errorf("the monochrome report has colours:\n%q", out)
+	}
+	if out := wrapped(Plain); strings.Contains(out, "\x1b[") {
+		t.Errorf("the plain report has escape codes:\n%q", out)
+	}
+}
@@ -0,0 +1,203 @@
+package termui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tunesday/internal/core"
+	"tunesday/internal/i18n"
+	"tunesday/internal/stats"
+)
+
+// ShowStatistics is the statistics dashboard. Each page is picked from a menu.
+func ShowStatistics(ctx context.Context, data *core.Data) {
+	for {
+		idx := ShowMenu(ctx, i18n.T("Statistics"), []string{
+			i18n.T("Leaderboard"),
+			i18n.T("Tunes over time"),
+			i18n.T("Fair share of draws"),
+			i18n.T("Days since last tune"),
+			i18n.T("Platforms"),
+			i18n.T("Tunesday Wrapped %d", con.Now().Year()),
+			i18n.T("Back"),
+		})
+		if idx == -1 {
+			quit()
+		}
+		if idx == -2 || idx == 6 {
+			return
+		}
+
+		db := stats.NewDashboard(data, con.Now())
+		ClearScreen()
+		PrintTunesdayHeader()
+		switch idx {
+		case 0:
+			printLeaderboard(db)
+		case 1:
+			printTunesOverTime(db)
+		case 2:
+			printFairShare(db)
+		case 3:
+			printDaysSince(db)
+		case 4:
+			printPlatforms(db)
+		case 5:
+			stats.NewWrapped(data, con.Now().Year()).WriteANSI(con.Out, termWidth(), ReportStyles())
+		}
+		PressEnterToContinue()
+	}
+}
+
+// ReportStyles are the skin's styles for reports drawn on the terminal, in
+// as many colours as it shows.
+func ReportStyles() stats.ANSIStyles {
+	return stats.ANSIStyles{Title: skin.Title.Render, Bar: skin.Accent.Render}
+}
+
+func statsNameWidth(db *stats.Dashboard) int {
+	w := len([]rune(i18n.T("Participant")))
+	for _, p := range db.Participants {
+		if n := len([]rune(p.Name)); n > w {
+			w = n
+		}
+	}
+	if w > 24 {
+		w = 24
+	}
+	return w
+}
+
+func printLeaderboard(db *stats.Dashboard) {
+	fmt.Fprintln(con.Out, i18n.T("Leaderboard"))
+	fmt.Fprintln(con.Out, "")
+	if len(db.Participants) == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No participants."))
+		return
+	}
+	nw := statsNameWidth(db)
+	max := 0
+	for _, p := range db.Participants {
+		if p.Counts.Delivered > max {
+			max = p.Counts.Delivered
+		}
+	}
+	cols := []string{i18n.T("Tunes"), i18n.T("Drawn"), i18n.T("Skipped"), i18n.T("Passed"), i18n.T("Volunteered")}
+	fmt.Fprintf(con.Out, "     %s  %s\n", PadRight(i18n.T("Participant"), nw), strings.Join(cols, "  "))
+	for i, p := range db.Participants {
+		c := p.Counts
+		fmt.Fprintf(con.Out, "%3d. %s  %s  %s\n", i+1, PadRight(TruncateRunes(p.Name, nw), nw),
+			numberColumns(cols, c.Delivered, c.Drawn, c.Skipped, c.Passed, c.Volunteered),
+			skin.Accent.Render(stats.Bar(c.Delivered, max, 15)))
+	}
+}
+
+func printTunesOverTime(db *stats.Dashboard) {
+	fmt.Fprintf(con.Out, "%s\n\n", i18n.T("Tunes per month since %s", i18n.MonthYear(db.MonthStart)))
+	nw := statsNameWidth(db)
+	var months strings.Builder
+	for i := 0; i < stats.Months; i++ {
+		months.WriteString(string([]rune(i18n.Month(db.MonthStart.AddDate(0, i, 0).Month()))[:1]))
+	}
+	fmt.Fprintf(con.Out, "%s  %s  %s\n", PadRight(i18n.T("Participant"), nw), months.String(), i18n.T("Total"))
+	max := 0
+	for _, p := range db.Participants {
+		for _, n := range p.Monthly {
+			if n > max {
+				max = n
+			}
+		}
+	}
+	for _, p := range db.Participants {
+		total := 0
+		for _, n := range p.Monthly {
+			total += n
+		}
+		fmt.Fprintf(con.Out, "%s  %s  %d\n", PadRight(TruncateRunes(p.Name, nw), nw), skin.Accent.Render(stats.Sparkline(p.Monthly, max)), total)
+	}
+}
+
+func printFairShare(db *stats.Dashboard) {
+	fmt.Fprintln(con.Out, i18n.T("Share of draws compared to a perfectly fair draw"))
+	fmt.Fprintf(con.Out, "(%s)\n\n", i18n.N(db.Draws, "%d draw recorded, volunteers and legacy counts left out",
+		"%d draws recorded, volunteers and legacy counts left out", db.Draws))
+	if db.Draws == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No draws yet."))
+		return
+	}
+	nw := statsNameWidth(db)
+	drawn, expected, fairness := i18n.T("Drawn"), i18n.T("Expected"), i18n.T("Fairness")
+	fmt.Fprintf(con.Out, "%s  %s  %s  %s\n", PadRight(i18n.T("Participant"), nw), drawn, expected, fairness)
+	for _, p := range db.Participants {
+		ratio := p.FairRatio()
+		mark := "="
+		switch {
+		case ratio > 1.2:
+			mark = i18n.T("▲ lucky")
+		case ratio < 0.8:
+			mark = i18n.T("▼ spared")
+		}
+		fmt.Fprintf(con.Out, "%s  %*d  %*.1f  %*.0f%% %s\n", PadRight(TruncateRunes(p.Name, nw), nw),
+			len([]rune(drawn)), p.Counts.Drawn, len([]rune(expected)), p.Expected, len([]rune(fairness))-4, ratio*100, mark)
+	}
+}
+
+func printDaysSince(db *stats.Dashboard) {
+	fmt.Fprintln(con.Out, i18n.T("Days since each participant last provided a tune"))
+	fmt.Fprintln(con.Out, "")
+	ps := append([]stats.ParticipantStats(nil), db.Participants...)
+	sort.SliceStable(ps, func(i, j int) bool {
+		a, b := ps[i].DaysSince, ps[j].DaysSince
+		if (a < 0) != (b < 0) {
+			return a < 0
+		}
+		return a > b
+	})
+	nw := statsNameWidth(db)
+	for _, p := range ps {
+		since := i18n.T("never")
+		if p.DaysSince >= 0 {
+			since = i18n.N(p.DaysSince, "%d day (%s)", "%d days (%s)", p.DaysSince, i18n.Date(p.LastTune))
+		}
+		status := ""
+		switch {
+		case p.Archived:
+			status = "  [" + i18n.T("archived") + "]"
+		case p.Disabled:
+			status = "  [" + i18n.T("deactivated") + "]"
+		}
+		fmt.Fprintf(con.Out, "  %s  %s%s\n", PadRight(TruncateRunes(p.Name, nw), nw), since, status)
+	}
+}
+
+func printPlatforms(db *stats.Dashboard) {
+	fmt.Fprintln(con.Out, i18n.T("Platforms"))
+	fmt.Fprintln(con.Out, "")
+	if len(db.Platforms) == 0 {
+		fmt.Fprintln(con.Out, i18n.T("No tunes yet."))
+		return
+	}
+	max, nw := 0, 0
+	for _, p := range db.Platforms {
+		if p.Count > max {
+			max = p.Count
+		}
+		if n := len([]rune(p.Name)); n > nw {
+			nw = n
+		}
+	}
+	for _, p := range db.Platforms {
+		fmt.Fprintf(con.Out, "  %s  %s %d\n", PadRight(p.Name, nw), skin.Accent.Render(stats.Bar(p.Count, max, 30)), p.Count)
+	}
+}
+
+// numberColumns right-aligns each number below its column heading.
+func numberColumns(headings []string, ns ...int) string {
+	cells := make([]string, len(ns))
+	for i, n := range ns {
+		cells[i] = fmt.Sprintf("%*d", len([]rune(headings[i])), n)
+	}
+	return strings.Join(cells, "  ")
+}
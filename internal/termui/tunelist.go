@@ -0,0 +1,427 @@
+package termui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"atomicgo.dev/keyboard/keys"
+
+	"tunesday/internal/core"
+	"tunesday/internal/i18n"
+)
+
+// tuneList is the state of the interactive tune list. It survives trips to
+// the detail view and the filter menu.
+type tuneList struct {
+	data   *core.Data
+	filter core.TuneFilter
+	order  core.TuneSort
+	rows   []int // positions in data.Tunes
+	cursor int
+	top    int
+}
+
+type listAction int
+
+const (
+	listQuit listAction = iota
+	listBack
+	listOpen
+	listOptions
+)
+
+// ListTunes is the interactive tune list: type to search, Tab for filters
+// and sorting, Enter for details.
+func ListTunes(ctx context.Context, data *core.Data, scanner *bufio.Scanner) {
+	l := &tuneList{data: data}
+	for {
+		l.refresh()
+		switch l.browse(ctx) {
+		case listQuit:
+			quit()
+		case listBack:
+			return
+		case listOpen:
+			TuneDetails(ctx, data, scanner, l.rows[l.cursor])
+		case listOptions:
+			l.options(ctx, scanner)
+		}
+	}
+}
+
+func (l *tuneList) refresh() {
+	l.rows = l.data.FilterTunes(l.filter, l.order)
+	if l.cursor >= len(l.rows) {
+		l.cursor = len(l.rows) - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+}
+
+// pageSize is the number of rows that fit below the header, the title and
+// search lines, the column headings and the footer.
+func (l *tuneList) pageSize() int {
+	n := termHeight() - headerHeight() - 5
+	if n < 5 {
+		n = 5
+	}
+	return n
+}
+
+func (l *tuneList) browse(ctx context.Context) listAction {
+	finished := make(chan listAction, 1)
+	l.draw()
+	stop := onResize(l.draw)
+	defer stop()
+
+	_ = con.Keys(func(key keys.Key) (bool, error) {
+		drawMu.Lock()
+		defer drawMu.Unlock()
+
+		page := l.pageSize()
+		switch key.Code {
+		case keys.Up:
+			l.cursor--
+		case keys.Down:
+			l.cursor++
+		case keys.PgUp:
+			l.cursor -= page
+		case keys.PgDown:
+			l.cursor += page
+		case keys.Home:
+			l.cursor = 0
+		case keys.End:
+			l.cursor = len(l.rows) - 1
+		case keys.RuneKey, keys.Space:
+			l.filter.Query += string(key.Runes)
+			l.cursor = 0
+			l.refresh()
+		case keys.Backspace, keys.CtrlH:
+			if q := []rune(l.filter.Query); len(q) > 0 {
+				l.filter.Query = string(q[:len(q)-1])
+				l.refresh()
+			}
+		case keys.CtrlU:
+			l.filter.Query = ""
+			l.refresh()
+		case keys.Enter:
+			if len(l.rows) == 0 {
+				break
+			}
+			finished <- listOpen
+			return true, nil
+		case keys.Tab:
+			finished <- listOptions
+			return true, nil
+		case keys.Esc:
+			if l.filter.Query != "" {
+				l.filter.Query = ""
+				l.refresh()
+				break
+			}
+			finished <- listBack
+			return true, nil
+		case keys.CtrlC:
+			finished <- listQuit
+			return true, nil
+		}
+		l.draw()
+		return false, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return listQuit
+	case a := <-finished:
+		return a
+	}
+}
+
+func (l *tuneList) draw() {
+	page := l.pageSize()
+	if l.cursor >= len(l.rows) {
+		l.cursor = len(l.rows) - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+	l.top = scrollWindow(l.cursor, l.top, page, len(l.rows))
+
+	ClearScreen()
+	PrintTunesdayHeader()
+	fmt.Fprint(con.Out, i18n.T("Tunes: %d of %d, %s", len(l.rows), len(l.data.Tunes), strings.ToLower(i18n.T(l.order.String()))))
+	if desc := l.describeFilter(); desc != "" {
+		fmt.Fprint(con.Out, " | "+desc)
+	}
+	fmt.Fprintln(con.Out, "")
+	fmt.Fprintln(con.Out, i18n.T("Search: %s", l.filter.Query+inputCursor()))
+
+	w := termWidth()
+	dateW, scoreW, whoW := 12, 9, 12
+	titleW := w - dateW - scoreW - whoW - 8
+	if titleW < 20 {
+		titleW = 20
+	}
+	fmt.Fprintln(con.Out, "  "+PadRight(i18n.T("Title"), titleW)+"  "+PadRight(i18n.T("By"), whoW)+"  "+PadRight(i18n.T("Date"), dateW)+"  "+i18n.T("Score"))
+	end := l.top + page
+	if end > len(l.rows) {
+		end = len(l.rows)
+	}
+	if len(l.rows) == 0 {
+		fmt.Fprintln(con.Out, "  "+i18n.T("No tunes match."))
+	}
+	for i := l.top; i < end; i++ {
+		t := l.data.Tunes[l.rows[i]]
+		date := ""
+		if !t.AddedAt.IsZero() {
+			date = i18n.Date(t.AddedAt)
+		}
+		line := PadRight(TruncateRunes(t.Title(), titleW), titleW) + "  " +
+			PadRight(TruncateRunes(l.data.ParticipantName(t.ParticipantID), whoW), whoW) + "  " +
+			PadRight(date, dateW) + "  " + t.ScoreLabel()
+		fmt.Fprintln(con.Out, cursorLine(line, i == l.cursor))
+	}
+	pages := (len(l.rows) + page - 1) / page
+	if pages > 1 {
+		fmt.Fprint(con.Out, i18n.T("Page %d/%d", l.top/page+1, pages)+"  ")
+	}
+	fmt.Fprintln(con.Out, skin.Muted.Render(i18n.T("Type to search · ↑↓ PgUp PgDn · Enter details · Tab filters & sort · Esc back")))
+}
+
+func (l *tuneList) describeFilter() string {
+	f := l.filter
+	var parts []string
+	if f.ParticipantID != "" {
+		parts = append(parts, i18n.T("by %s", l.data.ParticipantName(f.ParticipantID)))
+	}
+	if f.Platform != "" {
+		parts = append(parts, i18n.T("on %s", f.Platform))
+	}
+	if f.Theme != "" {
+		parts = append(parts, i18n.T("theme %s", f.Theme))
+	}
+	if !f.From.IsZero() || !f.To.IsZero() {
+		parts = append(parts, dateRangeText(f.From, f.To))
+	}
+	if f.MinScore != 0 {
+		parts = append(parts, ratingText(f.MinScore))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func dateRangeText(from, to time.Time) string {
+	switch {
+	case from.IsZero() && to.IsZero():
+		return i18n.T("all dates")
+	case to.IsZero():
+		return i18n.T("since %s", i18n.Date(from))
+	case from.IsZero():
+		return i18n.T("until %s", i18n.Date(to))
+	}
+	return i18n.T("%s to %s", i18n.Date(from), i18n.Date(to))
+}
+
+var ratingFilters = []float64{0, 4, 3, 2, core.Unrated}
+
+func ratingText(min float64) string {
+	switch min {
+	case 0:
+		return i18n.T("any rating")
+	case core.Unrated:
+		return i18n.T("unrated")
+	}
+	return i18n.T("rated %.0f+", min)
+}
+
+// options is the filter and sort menu of the tune list.
+func (l *tuneList) options(ctx context.Context, scanner *bufio.Scanner) {
+	for {
+		f := &l.filter
+		who := i18n.T("everyone")
+		if f.ParticipantID != "" {
+			who = l.data.ParticipantName(f.ParticipantID)
+		}
+		orAll := func(s string) string {
+			if s == "" {
+				return i18n.T("all")
+			}
+			return s
+		}
+		idx := ShowMenu(ctx, i18n.T("Filter and sort tunes"), []string{
+			i18n.T("Sort: %s", i18n.T(l.order.String())),
+			i18n.T("Date range: %s", dateRangeText(f.From, f.To)),
+			i18n.T("Participant: %s", who),
+			i18n.T("Platform: %s", orAll(f.Platform)),
+			i18n.T("Rating: %s", ratingText(f.MinScore)),
+			i18n.T("Theme: %s", orAll(f.Theme)),
+			i18n.T("Clear filters"),
+			i18n.T("Back to list"),
+		})
+		switch idx {
+		case -1:
+			quit()
+		case 0:
+			names := make([]string, len(core.TuneSorts))
+			for i, s := range core.TuneSorts {
+				names[i] = i18n.T(s.String())
+			}
+			if sel := ShowMenu(ctx, i18n.T("Sort by"), names); sel >= 0 {
+				l.order = core.TuneSorts[sel]
+			} else if sel == -1 {
+				quit()
+			}
+		case 1:
+			from, ok := readDate(scanner, i18n.T("From"), f.From)
+			if !ok {
+				continue
+			}
+			to, ok := readDate(scanner, i18n.T("To"), f.To)
+			if !ok {
+				continue
+			}
+			f.From, f.To = from, to
+		case 2:
+			// archived participants stay so that their tunes can be found
+			ps := append(l.data.CurrentParticipants(), l.data.ArchivedParticipants()...)
+			names := []string{i18n.T("Everyone")}
+			for _, p := range ps {
+				if p.Archived {
+					names = append(names, i18n.T("%s (archived)", p.Name))
+				} else {
+					names = append(names, p.Name)
+				}
+			}
+			switch sel := ShowMenu(ctx, i18n.T("Tunes brought by"), names); sel {
+			case -1:
+				quit()
+			case -2:
+			case 0:
+				f.ParticipantID = ""
+			default:
+				f.ParticipantID = ps[sel-1].ID
+			}
+		case 3:
+			platforms := l.data.Platforms()
+			switch sel := ShowMenu(ctx, i18n.T("Platform"), append([]string{i18n.T("All")}, platforms...)); sel {
+			case -1:
+				quit()
+			case -2:
+			case 0:
+				f.Platform = ""
+			default:
+				f.Platform = platforms[sel-1]
+			}
+		case 4:
+			names := make([]string, len(ratingFilters))
+			for i, r := range ratingFilters {
+				names[i] = ratingText(r)
+			}
+			if sel := ShowMenu(ctx, i18n.T("Rating"), names); sel >= 0 {
+				f.MinScore = ratingFilters[sel]
+			} else if sel == -1 {
+				quit()
+			}
+		case 5:
+			if theme, ok := pickThemeFilter(ctx, l.data, i18n.T("Theme")); ok {
+				f.Theme = theme
+			}
+		case 6:
+			l.filter = core.TuneFilter{Query: f.Query}
+		case 7, -2:
+			l.cursor, l.top = 0, 0
+			return
+		}
+	}
+}
+
+// readDate asks for a date, as YYYY-MM-DD or in the way of the language.
+// Enter keeps the current value and "-" clears it.
+func readDate(scanner *bufio.Scanner, label string, cur time.Time) (time.Time, bool) {
+	def := i18n.T("none")
+	if !cur.IsZero() {
+		def = i18n.Date(cur)
+	}
+	fmt.Fprint(con.Out, i18n.T("%s (e.g. %s, Enter keeps %s, - for none): ", label, i18n.DateHint(), def))
+	if !scanner.Scan() {
+		return cur, false
+	}
+	raw := strings.TrimSpace(scanner.Text())
+	switch raw {
+	case "":
+		return cur, true
+	case "-":
+		return time.Time{}, true
+	}
+	t, err := i18n.ParseDate(raw)
+	if err != nil {
+		fmt.Fprintln(con.Out, i18n.T("Please enter a date like %s.", i18n.DateHint()))
+		PressEnterToContinue()
+		return cur, false
+	}
+	return t, true
+}
+
+// TuneDetails shows everything known about the tune at position i and lets
+// the user edit its tags.
+func TuneDetails(ctx context.Context, data *core.Data, scanner *bufio.Scanner, i int) {
+	for {
+		t := data.Tunes[i]
+		details := func() int {
+			fmt.Fprintln(con.Out, skin.Title.Render(t.Title()))
+			fmt.Fprintln(con.Out, "")
+			lines := 3
+			row := func(label, value string) {
+				if value != "" {
+					fmt.Fprintf(con.Out, "  %-10s %s\n", label+":", value)
+					lines++
+				}
+			}
+			row(i18n.T("Link"), t.Link)
+			row(i18n.T("Platform"), t.Platform())
+			row(i18n.T("Channel"), t.Channel)
+			if t.Seconds > 0 {
+				row(i18n.T("Length"), fmt.Sprintf("%d:%02d", t.Seconds/60, t.Seconds%60))
+			}
+			row(i18n.T("By"), data.ParticipantName(t.ParticipantID))
+			if !t.AddedAt.IsZero() {
+				row(i18n.T("Added"), i18n.LongDate(t.AddedAt))
+			}
+			row(i18n.T("Theme"), t.Theme)
+			row(i18n.T("Tags"), strings.Join(t.Tags, ", "))
+			row(i18n.T("Score"), t.ScoreLabel())
+			var votes []string
+			for pid, v := range t.Votes {
+				name := data.ParticipantName(pid)
+				if name == "" {
+					name = i18n.T("unknown")
+				}
+				votes = append(votes, name+" "+stars(v))
+			}
+			sort.Strings(votes)
+			for _, v := range votes {
+				fmt.Fprintln(con.Out, "             "+v)
+			}
+			fmt.Fprintln(con.Out, "")
+			return lines + len(votes)
+		}
+		switch showMenu(ctx, "", []string{i18n.T("Edit tags"), i18n.T("Back")}, details) {
+		case -1:
+			quit()
+		case 0:
+			fmt.Fprint(con.Out, i18n.T("Tags, comma separated (currently: %s): ", strings.Join(t.Tags, ", ")))
+			if scanner.Scan() {
+				_ = rememberIf(data, func() error {
+					data.Tunes[i].Tags = core.ParseTags(scanner.Text())
+					return nil
+				}, "Edit tags of %s", t.Title())
+			}
+		default:
+			return
+		}
+	}
+}
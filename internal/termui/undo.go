@@ -0,0 +1,105 @@
+package termui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"tunesday/internal/core"
+	"tunesday/internal/i18n"
+)
+
+// history records the data before every change the screens make; nil
+// records nothing.
+var history *core.History
+
+// SetHistory makes the screens record their changes in h so that they can be
+// undone.
+func SetHistory(h *core.History) { history = h }
+
+// remember records data as it is before an action. Actions are named in
+// English whatever the language, like the changes made by commands and the
+// API, since the history is kept with the data.
+func remember(data *core.Data, action string, args ...any) {
+	if history != nil {
+		_ = history.Record(fmt.Sprintf(action, args...), data, con.Now())
+	}
+}
+
+// rememberIf runs change and records data as it was before in the history,
+// but only when change succeeds and changed something, so that neither a
+// failed action nor one that left the data as it was makes a step to undo
+// or drops the steps to redo.
+func rememberIf(data *core.Data, change func() error, action string, args ...any) error {
+	if history == nil {
+		return change()
+	}
+	before := data.Clone()
+	if err := change(); err != nil {
+		return err
+	}
+	if !changed(before, data) {
+		return nil
+	}
+	remember(before, action, args...)
+	return nil
+}
+
+// changed tells whether a and b differ, as they would be saved.
+func changed(a, b *core.Data) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return true
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return true
+	}
+	return !bytes.Equal(ja, jb)
+}
+
+// confirm asks a yes/no question about a destructive action. No is the
+// default, and Esc means no.
+func confirm(ctx context.Context, question, yes string) bool {
+	switch ShowMenu(ctx, question, []string{i18n.T("No, keep it"), yes}) {
+	case -1:
+		quit()
+	case 1:
+		return true
+	}
+	return false
+}
+
+// Undo undoes the last change and says what was undone.
+func Undo(data *core.Data) {
+	if history == nil {
+		return
+	}
+	c, err := history.Undo(data)
+	switch {
+	case errors.Is(err, core.ErrNothingToUndo):
+		fmt.Fprintln(con.Out, i18n.T("Nothing to undo."))
+	case err != nil:
+		fmt.Fprintln(con.Out, i18n.T("Could not undo: %v", err))
+	default:
+		fmt.Fprintln(con.Out, i18n.T("Undone: %s (%s)", c.Action, c.At.Format("15:04")))
+	}
+}
+
+// Redo redoes the last undone change and says what was redone.
+func Redo(data *core.Data) {
+	if history == nil {
+		return
+	}
+	c, err := history.Redo(data)
+	switch {
+	case errors.Is(err, core.ErrNothingToRedo):
+		fmt.Fprintln(con.Out, i18n.T("Nothing to redo."))
+	case err != nil:
+		fmt.Fprintln(con.Out, i18n.T("Could not redo: %v", err))
+	default:
+		fmt.Fprintln(con.Out, i18n.T("Redone: %s", c.Action))
+	}
+}
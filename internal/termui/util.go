@@ -1,35 +1,48 @@
 package termui
 
 import (
-    "bufio"
     "fmt"
-    "net/url"
     "os"
-    "strconv"
     "strings"
+
+    "tunesday/internal/i18n"
 )
 
 func ClearScreen() {
-    fmt.Print("\x1b[H\x1b[2J")
-    os.Stdout.Sync()
+    fmt.Fprint(con.Out, "\x1b[H\x1b[2J")
+    if f, ok := con.Out.(*os.File); ok {
+        f.Sync()
+    }
 }
 
 func PressEnterToContinue() {
-    fmt.Print("\nPress Enter to continue...")
-    bufio.NewScanner(os.Stdin).Scan()
+    fmt.Fprint(con.Out, "\n"+i18n.T("Press Enter to continue..."))
+    input.Scan()
+}
+
+// WarnProblems tells that the data has problems the doctor command can look
+// into, before the menu takes over the screen.
+func WarnProblems(n int) {
+    fmt.Fprintln(con.Out, skin.Banner.Render(" "+i18n.N(n,
+        "The data file has %d problem.", "The data file has %d problems.", n)+" "))
+    fmt.Fprintln(con.Out, i18n.T("Run \"tunesday doctor\" to see and repair them."))
+    PressEnterToContinue()
 }
 
-func HideCursor() { fmt.Print("\x1b[?25l") }
-func ShowCursor() { fmt.Print("\x1b[?25h") }
+func HideCursor() { fmt.Fprint(con.Out, "\x1b[?25l") }
+func ShowCursor() { fmt.Fprint(con.Out, "\x1b[?25h") }
+
+// TermWidth returns the width used for rendering, for callers outside termui.
+func TermWidth() int { return termWidth() }
 
-// termWidth returns terminal width from $COLUMNS when available, else 80.
 func termWidth() int {
-    if c := os.Getenv("COLUMNS"); c != "" {
-        if n, err := strconv.Atoi(c); err == nil && n > 20 {
-            return n
-        }
-    }
-    return 80
+    w, _ := con.Size()
+    return w
+}
+
+func termHeight() int {
+    _, h := con.Size()
+    return h
 }
 
 func centerText(width int, s string) string {
@@ -42,33 +55,65 @@ func centerText(width int, s string) string {
     return strings.Repeat(" ", pad) + s
 }
 
-func DrawBigWinner(name string) {
-    w := termWidth()
-    inner := w - 8
-    if inner < 20 { inner = 20 }
-    display := name + " is today's tune provider!!"
-    if len([]rune(display)) > inner-2 {
-        display = TruncateRunes(display, inner-2)
+// DrawBigWinner announces the drawn provider, with the round's theme if any.
+// The name is drawn in big letters when the terminal is wide enough.
+// It returns the number of lines printed.
+func DrawBigWinner(name, theme string) int {
+    lines := []string{i18n.T("%s is today's tune provider!!", name)}
+    if bigFont.Has(name) && bigFont.Width(name) <= bannerTextWidth() {
+        lines = nil
+        for _, row := range bigFont.Render(name) {
+            // the shading would only add noise inside the banner
+            lines = append(lines, strings.TrimPrefix(strings.ReplaceAll(row, "░", " "), " "))
+        }
+        lines = append(lines, "", i18n.T("is today's tune provider!!"))
+    }
+    if theme != "" {
+        lines = append(lines, i18n.T("Theme: %s", theme))
     }
+    return drawBanner(lines...)
+}
+
+// bannerTextWidth is how wide a line of text in a banner can be.
+func bannerTextWidth() int {
+    inner := termWidth() - 8
+    if inner < 20 { inner = 20 }
+    return inner - 6
+}
+
+// drawBanner prints the lines in a reverse-video box centred on the terminal
+// and returns the number of lines printed.
+func drawBanner(lines ...string) int {
+    w := termWidth()
+    inner := bannerTextWidth() + 6
     border := centerText(w, strings.Repeat("█", inner))
     padLine := centerText(w, "█"+strings.Repeat(" ", inner-2)+"█")
-    text := centerText(w, "█   "+display+strings.Repeat(" ", inner-4-len([]rune(display)))+"█")
-    fmt.Println("\x1b[1m\x1b[7m" + border + "\x1b[0m")
-    fmt.Println("\x1b[1m\x1b[7m" + padLine + "\x1b[0m")
-    fmt.Println("\x1b[1m\x1b[7m" + text + "\x1b[0m")
-    fmt.Println("\x1b[1m\x1b[7m" + padLine + "\x1b[0m")
-    fmt.Println("\x1b[1m\x1b[7m" + border + "\x1b[0m")
+    fmt.Fprintln(con.Out, skin.Banner.Render(border))
+    fmt.Fprintln(con.Out, skin.Banner.Render(padLine))
+    for _, display := range lines {
+        if len([]rune(display)) > inner-6 {
+            display = TruncateRunes(display, inner-6)
+        }
+        text := centerText(w, "█   "+display+strings.Repeat(" ", inner-5-len([]rune(display)))+"█")
+        fmt.Fprintln(con.Out, skin.Banner.Render(text))
+    }
+    fmt.Fprintln(con.Out, skin.Banner.Render(padLine))
+    fmt.Fprintln(con.Out, skin.Banner.Render(border))
+    return len(lines) + 4
 }
 
-func drawNameList(names []string, highlight int) {
+func drawNameList(names []string, highlight ...int) {
+    hi := make(map[int]bool, len(highlight))
+    for _, i := range highlight {
+        hi[i] = true
+    }
+    pad := strings.Repeat(" ", len([]rune(skin.Cursor)))
     for i, n := range names {
-        cursor := "  "
-        line := n
-        if i == highlight {
-            cursor = "▶ "
-            line = "\x1b[1m\x1b[36m" + n + "\x1b[0m"
+        if hi[i] {
+            fmt.Fprintln(con.Out, skin.Cursor + skin.Accent.Render(n))
+        } else {
+            fmt.Fprintln(con.Out, pad + n)
         }
-        fmt.Printf("%s%s\n", cursor, line)
     }
 }
 
@@ -94,32 +139,9 @@ func PadRight(s string, width int) string {
     return s + strings.Repeat(" ", width-len(r))
 }
 
-// linkDisplay returns a concise representation of the link for table view.
-// For valid YouTube https links, it shows youtu.be/{id}. Otherwise host+path.
-func linkDisplay(link string) string {
-    if link == "" { return "" }
-    if strings.HasPrefix(link, "https://www.youtube.") || strings.HasPrefix(link, "https://youtu.be/") || strings.Contains(link, "youtube.com") {
-        if i := strings.Index(link, "watch?v="); i != -1 {
-            id := link[i+8:]
-            if j := strings.IndexAny(id, "&#?"); j != -1 { id = id[:j] }
-            if id != "" { return "youtu.be/" + id }
-        }
-        if i := strings.Index(link, "/shorts/"); i != -1 {
-            id := link[i+8:]
-            if j := strings.Index(id, "/"); j != -1 { id = id[:j] }
-            if id != "" { return "youtu.be/" + id }
-        }
-        if strings.HasPrefix(link, "https://youtu.be/") {
-            id := strings.TrimPrefix(link, "https://youtu.be/")
-            if j := strings.Index(id, "?"); j != -1 { id = id[:j] }
-            if id != "" { return "youtu.be/" + id }
-        }
-    }
-    u, err := url.Parse(link)
-    if err != nil || u.Host == "" { return link }
-    host := strings.ToLower(u.Host)
-    host = strings.TrimPrefix(host, "www.")
-    host = strings.TrimPrefix(host, "m.")
-    path := u.EscapedPath()
-    return host + path
+// stars renders a 1-5 rating, e.g. ★★★☆☆.
+func stars(n int) string {
+    if n < 0 { n = 0 }
+    if n > 5 { n = 5 }
+    return strings.Repeat("★", n) + strings.Repeat("☆", 5-n)
 }
@@ -0,0 +1,104 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+// ArchiveVersion is the archive layout written by this build. Archives hold
+// manifest.json, describing the archive, and data.json, the data file as
+// tunesday saves it.
+const ArchiveVersion = 1
+
+const archiveFormat = "tunesday-archive"
+
+// Manifest describes an archive.
+type Manifest struct {
+	Format       string    `json:"format"`
+	Version      int       `json:"version"`      // archive layout
+	DataVersion  int       `json:"data_version"` // format of data.json, see core.CurrentVersion
+	ExportedAt   time.Time `json:"exported_at"`
+	Participants int       `json:"participants"`
+	Tunes        int       `json:"tunes"`
+	Sessions     int       `json:"sessions"`
+	Themes       int       `json:"themes"`
+}
+
+func writeArchive(w io.Writer, d *core.Data) error {
+	now := time.Now()
+	m := Manifest{
+		Format:       archiveFormat,
+		Version:      ArchiveVersion,
+		DataVersion:  core.CurrentVersion,
+		ExportedAt:   now,
+		Participants: len(d.Participants),
+		Tunes:        len(d.Tunes),
+		Sessions:     len(d.Sessions),
+		Themes:       len(d.Themes),
+	}
+	zw := zip.NewWriter(w)
+	for _, f := range []struct {
+		name string
+		v    any
+	}{{"manifest.json", m}, {"data.json", d}} {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.v); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func readArchive(r io.Reader) (*core.Data, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, fmt.Errorf("not a tunesday archive: %w", err)
+	}
+	var m Manifest
+	if err := readJSON(zr, "manifest.json", &m); err != nil {
+		return nil, err
+	}
+	if m.Format != archiveFormat {
+		return nil, fmt.Errorf("not a tunesday archive: format %q", m.Format)
+	}
+	if m.Version > ArchiveVersion || m.DataVersion > core.CurrentVersion {
+		return nil, fmt.Errorf("the archive was written by a newer tunesday (archive version %d, data version %d), update to import it", m.Version, m.DataVersion)
+	}
+	var d core.Data
+	if err := readJSON(zr, "data.json", &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func readJSON(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("not a tunesday archive: %s is missing", name)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
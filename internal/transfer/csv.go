@@ -0,0 +1,293 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+var (
+	tuneColumns        = []string{"name", "link", "id", "participant", "channel", "seconds", "theme", "tags", "votes", "added_at"}
+	participantColumns = []string{"name", "handle", "email", "chat_user_id", "joined_at", "disabled", "archived", "archived_at", "legacy_draws"}
+)
+
+// aliases are other headers that spreadsheets use for the columns.
+var aliases = map[string]string{
+	"title":    "name",
+	"tune":     "name",
+	"url":      "link",
+	"video_id": "id",
+	"provider": "participant",
+	"by":       "participant",
+	"artist":   "channel",
+	"date":     "added_at",
+	"added":    "added_at",
+}
+
+func writeTunesCSV(w io.Writer, d *core.Data) error {
+	cw := csv.NewWriter(w)
+	cw.Write(tuneColumns)
+	for _, t := range d.Tunes {
+		var votes []string
+		for id, stars := range t.Votes {
+			votes = append(votes, fmt.Sprintf("%s=%d", d.ParticipantName(id), stars))
+		}
+		sort.Strings(votes)
+		cw.Write([]string{
+			t.Name,
+			t.Link,
+			t.ID,
+			d.ParticipantName(t.ParticipantID),
+			t.Channel,
+			number(t.Seconds),
+			t.Theme,
+			strings.Join(t.Tags, "; "),
+			strings.Join(votes, "; "),
+			timestamp(t.AddedAt),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeParticipantsCSV(w io.Writer, d *core.Data) error {
+	cw := csv.NewWriter(w)
+	cw.Write(participantColumns)
+	for _, p := range d.SortedParticipants() {
+		cw.Write([]string{
+			p.Name,
+			p.Handle,
+			p.Email,
+			p.ChatUserID,
+			timestamp(p.JoinedAt),
+			flag(p.Disabled),
+			flag(p.Archived),
+			timestamp(p.ArchivedAt),
+			number(p.LegacyDraws),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func number(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func flag(b bool) string {
+	if b {
+		return "yes"
+	}
+	return ""
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// readCSV reads a tunes or a participants table, telling them apart by the
+// header. Columns are matched by name, in any order; unknown ones are skipped.
+func readCSV(r io.Reader) (*core.Data, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		h = strings.ReplaceAll(h, " ", "_")
+		if a, ok := aliases[h]; ok {
+			h = a
+		}
+		if _, dup := col[h]; !dup {
+			col[h] = i
+		}
+	}
+	_, hasLink := col["link"]
+	_, hasName := col["name"]
+	switch {
+	case hasLink:
+		return readTunes(cr, col)
+	case hasName:
+		return readParticipants(cr, col)
+	}
+	return nil, errors.New("the CSV header has neither a link column (tunes) nor a name column (participants)")
+}
+
+// row is a CSV record with its columns looked up by name.
+type row struct {
+	line   int
+	fields []string
+	col    map[string]int
+}
+
+func (r row) get(name string) string {
+	i, ok := r.col[name]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+func (r row) int(name string) (int, error) {
+	s := r.get(name)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: %s: %q is not a number", r.line, name, s)
+	}
+	return n, nil
+}
+
+func (r row) bool(name string) bool {
+	switch strings.ToLower(r.get(name)) {
+	case "yes", "y", "true", "1", "x":
+		return true
+	}
+	return false
+}
+
+// dateLayouts are the ways spreadsheets write dates.
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "02.01.2006", "1/2/2006"}
+
+func (r row) time(name string) (time.Time, error) {
+	s := r.get(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("line %d: %s: %q is not a date like 2006-01-02", r.line, name, s)
+}
+
+func readRows(cr *csv.Reader, col map[string]int, each func(row) error) error {
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		if strings.TrimSpace(strings.Join(fields, "")) == "" {
+			continue
+		}
+		if err := each(row{line: line, fields: fields, col: col}); err != nil {
+			return err
+		}
+	}
+}
+
+func readTunes(cr *csv.Reader, col map[string]int) (*core.Data, error) {
+	d := core.NewData()
+	person := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		if p := d.ParticipantByName(name); p != nil {
+			return p.ID
+		}
+		p, _ := d.AddParticipant(name)
+		p.JoinedAt = time.Time{}
+		return p.ID
+	}
+	err := readRows(cr, col, func(r row) error {
+		t := core.Tune{
+			Name:          r.get("name"),
+			Link:          r.get("link"),
+			ID:            r.get("id"),
+			ParticipantID: person(r.get("participant")),
+			Channel:       r.get("channel"),
+			Theme:         r.get("theme"),
+			Tags:          core.ParseTags(strings.ReplaceAll(r.get("tags"), ";", ",")),
+			Provider:      "manual",
+		}
+		if t.Link == "" {
+			return fmt.Errorf("line %d: the tune has no link", r.line)
+		}
+		if t.ID != "" {
+			t.Provider = "youtube"
+		}
+		var err error
+		if t.Seconds, err = r.int("seconds"); err != nil {
+			return err
+		}
+		if t.AddedAt, err = r.time("added_at"); err != nil {
+			return err
+		}
+		for _, v := range strings.Split(r.get("votes"), ";") {
+			name, stars, ok := strings.Cut(strings.TrimSpace(v), "=")
+			if !ok {
+				continue
+			}
+			n, err := core.ParseRating(strings.TrimSpace(stars))
+			if err != nil {
+				return fmt.Errorf("line %d: votes: %w", r.line, err)
+			}
+			if t.Votes == nil {
+				t.Votes = make(map[string]int)
+			}
+			t.Votes[person(strings.TrimSpace(name))] = n
+		}
+		d.Tunes = append(d.Tunes, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func readParticipants(cr *csv.Reader, col map[string]int) (*core.Data, error) {
+	d := core.NewData()
+	err := readRows(cr, col, func(r row) error {
+		p, err := d.AddParticipant(r.get("name"))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", r.line, err)
+		}
+		p.Handle = r.get("handle")
+		p.Email = r.get("email")
+		p.ChatUserID = r.get("chat_user_id")
+		p.Disabled = r.bool("disabled")
+		p.Archived = r.bool("archived")
+		if p.JoinedAt, err = r.time("joined_at"); err != nil {
+			return err
+		}
+		if p.ArchivedAt, err = r.time("archived_at"); err != nil {
+			return err
+		}
+		if p.LegacyDraws, err = r.int("legacy_draws"); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,227 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Link is a tune found in a list of links, a chat export or a playlist feed.
+type Link struct {
+	URL      string
+	Title    string        // when the source names the video
+	Channel  string        // uploader of the video, when the source names it
+	Author   string        // who posted it, as the source shows them
+	AuthorID string        // the chat's ID of the author, when there is one
+	At       time.Time     // when it was posted; zero when unknown
+	Duration time.Duration // length of the video, when the source tells
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>|"'\]\[()]+`)
+
+// ReadLinks finds the links in r, which is one of:
+//   - a chat export as JSON, from Slack, Discord (DiscordChatExporter) or
+//     Telegram; every link in a message counts,
+//   - a YouTube playlist feed (Atom),
+//   - text with a link per line, optionally preceded by the date it was
+//     picked and who picked it: "2023-05-02 Ann https://youtu.be/…".
+//     Lines starting with # are skipped.
+func ReadLinks(r io.Reader) ([]Link, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimPrefix(b, []byte("\ufeff"))
+	switch t := bytes.TrimSpace(b); {
+	case len(t) == 0:
+		return nil, nil
+	case t[0] == '[' || t[0] == '{':
+		return readChat(t)
+	case t[0] == '<':
+		return readFeed(t)
+	}
+	return readText(b), nil
+}
+
+func readText(b []byte) []Link {
+	var out []Link
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		loc := urlPattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		l := Link{URL: trimURL(line[loc[0]:loc[1]])}
+		var author []string
+		for _, f := range strings.Fields(line[:loc[0]]) {
+			if at, ok := parseDate(f); ok && l.At.IsZero() && len(author) == 0 {
+				l.At = at
+				continue
+			}
+			author = append(author, f)
+		}
+		l.Author = strings.Trim(strings.Join(author, " "), " :-–")
+		out = append(out, l)
+	}
+	return out
+}
+
+// trimURL drops punctuation that ends the sentence around a link.
+func trimURL(u string) string {
+	return strings.TrimRight(u, ".,;:!?")
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimRight(s, ",:")
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// chatMessage has the fields of the chat exports that matter here.
+type chatMessage struct {
+	// Slack
+	TS          string `json:"ts"`
+	User        string `json:"user"`
+	UserName    string `json:"user_name"`
+	UserProfile struct {
+		RealName    string `json:"real_name"`
+		DisplayName string `json:"display_name"`
+	} `json:"user_profile"`
+	// Slack and Telegram; Telegram's is a list of parts for formatted text
+	Text json.RawMessage `json:"text"`
+	// Discord
+	Timestamp string `json:"timestamp"`
+	Content   string `json:"content"`
+	Author    struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	} `json:"author"`
+	// Telegram
+	Date   string `json:"date"`
+	From   string `json:"from"`
+	FromID string `json:"from_id"`
+}
+
+func readChat(b []byte) ([]Link, error) {
+	var msgs []chatMessage
+	if b[0] == '[' {
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			return nil, fmt.Errorf("chat export: %w", err)
+		}
+	} else {
+		var export struct {
+			Messages []chatMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(b, &export); err != nil {
+			return nil, fmt.Errorf("chat export: %w", err)
+		}
+		msgs = export.Messages
+	}
+
+	var out []Link
+	for _, m := range msgs {
+		l := Link{
+			Author:   first(m.Author.Nickname, m.Author.Name, m.UserProfile.DisplayName, m.UserProfile.RealName, m.UserName, m.From),
+			AuthorID: first(m.Author.ID, m.User, m.FromID),
+			At:       chatTime(m),
+		}
+		text := m.Content
+		if len(m.Text) > 0 {
+			var s string
+			if json.Unmarshal(m.Text, &s) == nil {
+				text += " " + s
+			} else {
+				// Telegram's parts, where links can sit in text or href
+				text += " " + strings.ReplaceAll(string(m.Text), `\/`, "/")
+			}
+		}
+		for _, u := range urlPattern.FindAllString(text, -1) {
+			l.URL = trimURL(u)
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func chatTime(m chatMessage) time.Time {
+	if m.TS != "" {
+		// Slack: seconds since 1970 with microseconds after the dot
+		sec, _, _ := strings.Cut(m.TS, ".")
+		if n, err := strconv.ParseInt(sec, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+	}
+	for _, s := range []string{m.Timestamp, m.Date} {
+		if s == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+		// Telegram writes local time without a zone
+		if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func first(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// feed is the part of a YouTube playlist feed that matters here.
+type feed struct {
+	Entries []struct {
+		VideoID string `xml:"videoId"`
+		Title   string `xml:"title"`
+		Link    struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Author struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+	} `xml:"entry"`
+}
+
+func readFeed(b []byte) ([]Link, error) {
+	var f feed
+	if err := xml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("playlist feed: %w", err)
+	}
+	var out []Link
+	for _, e := range f.Entries {
+		u := e.Link.Href
+		if e.VideoID != "" {
+			u = "https://www.youtube.com/watch?v=" + e.VideoID
+		}
+		if u == "" {
+			continue
+		}
+		// the feed's author is the uploader, not whoever added it to the
+		// list, and it was published when it was uploaded, not picked
+		out = append(out, Link{URL: u, Title: strings.TrimSpace(e.Title), Channel: e.Author.Name})
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Link
+	}{
+		{
+			name: "text",
+			in: "# our picks\n" +
+				"https://youtu.be/a\n" +
+				"2023-05-02 Ann Smith: https://www.youtube.com/watch?v=b&t=1.\n" +
+				"no link here\n",
+			want: []Link{
+				{URL: "https://youtu.be/a"},
+				{URL: "https://www.youtube.com/watch?v=b&t=1", Author: "Ann Smith", At: time.Date(2023, 5, 2, 0, 0, 0, 0, time.Local)},
+			},
+		},
+		{
+			name: "slack",
+			in:   `[{"user": "U1", "user_profile": {"real_name": "Ann"}, "ts": "1683043200.000100", "text": "today: <https:\/\/youtu.be\/a|youtu.be/a> and <https://youtu.be/b>"}]`,
+			want: []Link{
+				{URL: "https://youtu.be/a", Author: "Ann", AuthorID: "U1", At: time.Unix(1683043200, 0)},
+				{URL: "https://youtu.be/b", Author: "Ann", AuthorID: "U1", At: time.Unix(1683043200, 0)},
+			},
+		},
+		{
+			name: "discord",
+			in:   `{"messages": [{"timestamp": "2023-05-02T19:00:00+02:00", "content": "https://youtu.be/a", "author": {"id": "42", "name": "ann", "nickname": "Ann"}}]}`,
+			want: []Link{{URL: "https://youtu.be/a", Author: "Ann", AuthorID: "42", At: time.Date(2023, 5, 2, 17, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name: "telegram",
+			in:   `{"messages": [{"date": "2023-05-02T19:00:00", "from": "Bob", "from_id": "user7", "text": ["hear ", {"type": "link", "text": "https://youtu.be/b"}]}]}`,
+			want: []Link{{URL: "https://youtu.be/b", Author: "Bob", AuthorID: "user7", At: time.Date(2023, 5, 2, 19, 0, 0, 0, time.Local)}},
+		},
+		{
+			name: "feed",
+			in: `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+ <entry>
+  <yt:videoId>a</yt:videoId>
+  <title>Song A</title>
+  <link rel="alternate" href="https://www.youtube.com/watch?v=a"/>
+  <author><name>Band</name></author>
+  <published>2023-05-02T19:00:00+00:00</published>
+ </entry>
+</feed>`,
+			want: []Link{{URL: "https://www.youtube.com/watch?v=a", Title: "Song A", Channel: "Band"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadLinks(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d links %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.URL != w.URL || g.Title != w.Title || g.Channel != w.Channel || g.Author != w.Author ||
+					g.AuthorID != w.AuthorID || !g.At.Equal(w.At) {
+					t.Errorf("link %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"tunesday/internal/core"
+)
+
+// The NDJSON export starts with a header line, then has one line per
+// participant, theme, session and tune, each with a "type" field:
+//
+//	{"type":"tunesday","version":3}
+//	{"type":"participant","id":"…","name":"Ann"}
+//	{"type":"tune","name":"…","link":"…","participant_id":"…"}
+const ndjsonHeader = "tunesday"
+
+func writeNDJSON(w io.Writer, d *core.Data) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	write := func(v any) {
+		// errors stick to bw and come out of Flush
+		_ = enc.Encode(v)
+	}
+	write(struct {
+		Type    string `json:"type"`
+		Version int    `json:"version"`
+	}{ndjsonHeader, core.CurrentVersion})
+	for _, p := range d.SortedParticipants() {
+		write(struct {
+			Type string `json:"type"`
+			*core.Participant
+		}{"participant", p})
+	}
+	for _, t := range d.Themes {
+		write(struct {
+			Type string `json:"type"`
+			*core.Theme
+		}{"theme", t})
+	}
+	for _, s := range d.Sessions {
+		write(struct {
+			Type string `json:"type"`
+			*core.Session
+		}{"session", s})
+	}
+	for i := range d.Tunes {
+		write(struct {
+			Type string `json:"type"`
+			*core.Tune
+		}{"tune", &d.Tunes[i]})
+	}
+	return bw.Flush()
+}
+
+func readNDJSON(r io.Reader) (*core.Data, error) {
+	d := core.NewData()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		b := sc.Bytes()
+		if strings.TrimSpace(string(b)) == "" {
+			continue
+		}
+		var head struct {
+			Type    string `json:"type"`
+			Version int    `json:"version"`
+		}
+		if err := json.Unmarshal(b, &head); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		var err error
+		switch head.Type {
+		case ndjsonHeader:
+			if head.Version > core.CurrentVersion {
+				return nil, fmt.Errorf("line %d: written by a newer tunesday (data version %d), update to import it", line, head.Version)
+			}
+		case "participant":
+			var p core.Participant
+			if err = json.Unmarshal(b, &p); err == nil {
+				if p.ID == "" {
+					p.ID = core.NewID()
+				}
+				d.Participants[p.ID] = &p
+			}
+		case "theme":
+			var t core.Theme
+			if err = json.Unmarshal(b, &t); err == nil {
+				d.Themes = append(d.Themes, &t)
+			}
+		case "session":
+			var s core.Session
+			if err = json.Unmarshal(b, &s); err == nil {
+				d.Sessions = append(d.Sessions, &s)
+			}
+		case "tune":
+			var t core.Tune
+			if err = json.Unmarshal(b, &t); err == nil {
+				d.Tunes = append(d.Tunes, t)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, head.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
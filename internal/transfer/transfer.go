@@ -0,0 +1,99 @@
+// Package transfer moves Tunesday data in and out of portable formats: CSV
+// for spreadsheets, NDJSON for scripts and a versioned archive that holds
+// everything.
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"tunesday/internal/core"
+)
+
+// Format is a file format data can be exported to and imported from.
+type Format string
+
+const (
+	CSV     Format = "csv"     // one table, tunes or participants
+	NDJSON  Format = "ndjson"  // one JSON record per line
+	Archive Format = "archive" // zip with a manifest and the whole data set
+)
+
+// Formats lists the formats, for help texts.
+var Formats = []Format{CSV, NDJSON, Archive}
+
+// ParseFormat checks a format name given by the user.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q, want csv, ndjson or archive", s)
+}
+
+// FormatOf guesses the format from a file name.
+func FormatOf(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV, true
+	case ".ndjson", ".jsonl":
+		return NDJSON, true
+	case ".zip", ".tunesday":
+		return Archive, true
+	}
+	return "", false
+}
+
+// Table is what a CSV file holds.
+type Table string
+
+const (
+	Tunes        Table = "tunes"
+	Participants Table = "participants"
+)
+
+// Check returns an error unless t is a table Export knows; "" means tunes.
+func (t Table) Check() error {
+	switch t {
+	case Tunes, Participants, "":
+		return nil
+	}
+	return fmt.Errorf("unknown table %q, want tunes or participants", string(t))
+}
+
+// Export writes d in format f. CSV holds only one table; the other formats
+// ignore table.
+func Export(w io.Writer, d *core.Data, f Format, table Table) error {
+	switch f {
+	case CSV:
+		if err := table.Check(); err != nil {
+			return err
+		}
+		if table == Participants {
+			return writeParticipantsCSV(w, d)
+		}
+		return writeTunesCSV(w, d)
+	case NDJSON:
+		return writeNDJSON(w, d)
+	case Archive:
+		return writeArchive(w, d)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+// Import reads data in format f. What a CSV file holds is told by its
+// header; tunes bring along the participants named in them.
+func Import(r io.Reader, f Format) (*core.Data, error) {
+	switch f {
+	case CSV:
+		return readCSV(r)
+	case NDJSON:
+		return readNDJSON(r)
+	case Archive:
+		return readArchive(r)
+	}
+	return nil, fmt.Errorf("unknown format %q", f)
+}
@@ -0,0 +1,121 @@
+package transfer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tunesday/internal/core"
+)
+
+func sample() *core.Data {
+	at := time.Date(2026, 10, 13, 19, 0, 0, 0, time.UTC)
+	d := core.NewData()
+	d.Participants["a"] = &core.Participant{ID: "a", Name: "Ann", Handle: "annie", JoinedAt: at}
+	d.Participants["b"] = &core.Participant{ID: "b", Name: "Bob", Archived: true, ArchivedAt: at, LegacyDraws: 3}
+	d.Themes = []*core.Theme{{ID: "t", Name: "Covers", CooldownWeeks: 8}}
+	d.Sessions = []*core.Session{{ID: "s", Date: at, Theme: "Covers", ThemeID: "t", Draws: []*core.Draw{{ParticipantID: "a", At: at, Outcome: core.OutcomeDelivered}}}}
+	d.Tunes = []core.Tune{
+		{Name: "One, with a comma", Link: "https://youtu.be/one", ID: "one", Provider: "youtube", ParticipantID: "a",
+			Channel: "Band", Seconds: 200, SessionID: "s", Theme: "Covers", Tags: []string{"cover", "live"},
+			Votes: map[string]int{"a": 4, "b": 5}, AddedAt: at},
+		{Name: "Two", Link: "https://example.com/two", Provider: "manual", ParticipantID: "b"},
+	}
+	return d
+}
+
+func TestRoundTripKeepsEverything(t *testing.T) {
+	for _, f := range []Format{NDJSON, Archive} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Export(&buf, sample(), f, ""); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Import(&buf, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := core.Diff(sample(), got); len(diff) > 0 {
+				t.Errorf("round trip changed the data: %q", diff)
+			}
+		})
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var tunes, people bytes.Buffer
+	if err := Export(&tunes, sample(), CSV, Tunes); err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(&people, sample(), CSV, Participants); err != nil {
+		t.Fatal(err)
+	}
+	wantTunes := `name,link,id,participant,channel,seconds,theme,tags,votes,added_at
+"One, with a comma",https://youtu.be/one,one,Ann,Band,200,Covers,cover; live,Ann=4; Bob=5,2026-10-13T19:00:00Z
+Two,https://example.com/two,,Bob,,,,,,
+`
+	if tunes.String() != wantTunes {
+		t.Errorf("tunes CSV:\n%s\nwant\n%s", tunes.String(), wantTunes)
+	}
+
+	d, err := Import(&tunes, CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann, bob := d.ParticipantByName("Ann"), d.ParticipantByName("Bob")
+	if ann == nil || bob == nil || len(d.Tunes) != 2 {
+		t.Fatalf("imported %+v", d)
+	}
+	one := d.Tunes[0]
+	if one.Name != "One, with a comma" || one.ParticipantID != ann.ID || one.Votes[bob.ID] != 5 ||
+		one.Seconds != 200 || strings.Join(one.Tags, ",") != "cover,live" || one.Provider != "youtube" {
+		t.Errorf("tune = %+v", one)
+	}
+
+	d, err = Import(&people, CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob = d.ParticipantByName("Bob")
+	if len(d.Participants) != 2 || bob == nil || !bob.Archived || bob.LegacyDraws != 3 || d.ParticipantByName("annie") == nil {
+		t.Errorf("participants = %+v", d.Participants)
+	}
+}
+
+func TestImportSpreadsheet(t *testing.T) {
+	sheet := "\ufeffDate;Title;URL;Provider\n" +
+		"2024-03-05;Song A;https://youtu.be/a;Ann\n" +
+		";;;\n" +
+		"12.03.2024;Song B;https://example.com/b;ann\n"
+	r := strings.NewReader(strings.ReplaceAll(sheet, ";", ","))
+	d, err := Import(r, CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Participants) != 1 || len(d.Tunes) != 2 {
+		t.Fatalf("imported %d participants, %d tunes", len(d.Participants), len(d.Tunes))
+	}
+	if b := d.Tunes[1]; b.Name != "Song B" || b.AddedAt.Month() != time.March || b.ParticipantID != d.Tunes[0].ParticipantID {
+		t.Errorf("second tune = %+v", b)
+	}
+
+	_, err = Import(strings.NewReader("title,url,seconds\nA,https://a,long\n"), CSV)
+	if err == nil || !strings.Contains(err.Error(), `line 2: seconds: "long" is not a number`) {
+		t.Errorf("bad number: %v", err)
+	}
+}
+
+func TestImportRejectsNewerVersions(t *testing.T) {
+	_, err := Import(strings.NewReader(`{"type":"tunesday","version":99}`+"\n"), NDJSON)
+	if err == nil || !strings.Contains(err.Error(), "newer tunesday") {
+		t.Errorf("NDJSON from the future: %v", err)
+	}
+	_, err = Import(strings.NewReader(`{"type":"tune"}`+"\n"+`{"type":"song"}`), NDJSON)
+	if err == nil || !strings.Contains(err.Error(), `line 2: unknown record type "song"`) {
+		t.Errorf("unknown record: %v", err)
+	}
+	if _, err := Import(strings.NewReader("not a zip"), Archive); err == nil {
+		t.Error("imported an archive that isn't one")
+	}
+}